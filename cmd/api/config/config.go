@@ -1,151 +1,592 @@
 package config
 
 import (
-	"context"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
 
 	"github.com/joho/godotenv"
-	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"gopkg.in/yaml.v3"
 )
 
+// DefaultConfigFile es el archivo de configuración usado si no se indica otro
+const DefaultConfigFile = "config.yaml"
+
 // Config contiene toda la configuración de la aplicación
 type Config struct {
 	// API Keys
-	OpenAIAPIKey   string
-	PineconeAPIKey string
+	OpenAIAPIKey    string `yaml:"openai_api_key"`
+	PineconeAPIKey  string `yaml:"pinecone_api_key"`
+	AnthropicAPIKey string `yaml:"anthropic_api_key"`
 
 	// Pinecone
-	IndexName          string
-	EmbeddingModel     string
-	EmbeddingDimension int
+	IndexName          string `yaml:"index_name"`
+	EmbeddingModel     string `yaml:"embedding_model"`
+	EmbeddingDimension int    `yaml:"embedding_dimension"`
+
+	// OpenAI Chat: modelo del proveedor "openai" si no define uno propio
+	ChatModel string `yaml:"chat_model"`
+
+	// Proveedores de chat y orden de fallback
+	Chat ChatConfig `yaml:"chat"`
+
+	// Parámetros de generación, por defecto y por modelo de chat
+	Generation GenerationConfig `yaml:"generation"`
+
+	// Opciones de búsqueda por solicitud
+	Search SearchConfig `yaml:"search"`
+
+	// Templates de prompt para la respuesta generada
+	Prompts PromptsConfig `yaml:"prompts"`
+
+	// Pistas de subtítulos por idioma
+	Subtitles SubtitlesConfig `yaml:"subtitles"`
+
+	// Transcripción legible armada con los cues
+	Transcript TranscriptConfig `yaml:"transcript"`
+
+	// Traducción de pistas de subtítulos con el modelo de chat
+	Translation TranslationConfig `yaml:"translation"`
+
+	// Resúmenes de video generados desde la transcripción
+	Summary SummaryConfig `yaml:"summary"`
+
+	// Capítulos de video detectados desde la transcripción
+	Chapters ChaptersConfig `yaml:"chapters"`
+
+	// Renditions HLS guardadas junto a cada video
+	HLS HLSConfig `yaml:"hls"`
 
-	// OpenAI Chat
-	ChatModel      string
-	ChatPricePer1K float64
+	// Variantes de miniaturas redimensionadas y placeholders
+	Thumbnails ThumbnailsConfig `yaml:"thumbnails"`
 
 	// Umbrales y límites
-	MinScoreThreshold float64
-	MaxTopK           int
-	DefaultTopK       int
+	MinScoreThreshold float64 `yaml:"min_score_threshold"`
+	MaxTopK           int     `yaml:"max_top_k"`
+	DefaultTopK       int     `yaml:"default_top_k"`
 
-	// Precios
-	EmbeddingPricePer1K float64
+	// Precios por modelo: se toman de pricing si está definido, si no de pricing_file
+	PricingFile string                       `yaml:"pricing_file"`
+	Pricing     map[string]models.ModelPrice `yaml:"pricing,omitempty"`
 
 	// Servidor
-	Port string
+	Port string `yaml:"port"`
 
 	// Rutas
-	VideosPath string
+	VideosPath string `yaml:"videos_path"`
+
+	// Clientes
+	APIKeys map[string]string `yaml:"api_keys"` // nombre del cliente -> API key
+
+	// Rate limiting
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+
+	// Cache HTTP por tipo de recurso
+	HTTPCache HTTPCacheConfig `yaml:"http_cache"`
+
+	// Presupuestos
+	Budget BudgetConfig `yaml:"budget"`
+
+	// Ledger de uso
+	UsageLedgerPath string `yaml:"usage_ledger_path"`
+
+	// Administración
+	AdminAPIKey string `yaml:"admin_api_key"`
+
+	// Logging
+	Logging LoggingConfig `yaml:"logging"`
+
+	// CORS
+	CORS CORSConfig `yaml:"cors"`
+
+	// Idioma de los mensajes cuando la solicitud no indica uno soportado
+	DefaultLanguage string `yaml:"default_language"`
 }
 
-// LoadConfig carga la configuración desde variables de entorno
-func LoadConfig() (Config, error) {
-	var config Config
+// ChatConfig define los proveedores de chat. Fallback es el orden en que se prueban:
+// si uno falla o no responde en TimeoutSeconds se pasa al siguiente.
+type ChatConfig struct {
+	Fallback       []string                      `yaml:"fallback"`
+	TimeoutSeconds int                           `yaml:"timeout_seconds"`
+	Providers      map[string]ChatProviderConfig `yaml:"providers"`
+}
 
-	if err := godotenv.Load(); err != nil {
-		return config, fmt.Errorf("no se pudo cargar archivo .env: %v", err)
-	}
+// ChatProviderConfig define un proveedor de chat.
+// Type es openai (cualquier API compatible con OpenAI), anthropic o echo (stub local de prueba).
+type ChatProviderConfig struct {
+	Type    string            `yaml:"type"`
+	BaseURL string            `yaml:"base_url,omitempty"`
+	APIKey  string            `yaml:"api_key,omitempty"`
+	Model   string            `yaml:"model,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
 
-	// Variables requeridas
-	config.OpenAIAPIKey = getEnvOrDefault("OPENAI_API_KEY", "")
-	config.PineconeAPIKey = getEnvOrDefault("PINECONE_API_KEY", "")
-	config.IndexName = getEnvOrDefault("INDEX_NAME", "")
-	config.EmbeddingModel = getEnvOrDefault("EMBEDDING_MODEL", "")
-	config.ChatModel = getEnvOrDefault("CHAT_MODEL", "")
-	config.Port = getEnvOrDefault("PORT", "")
-	config.VideosPath = getEnvOrDefault("VIDEOS_PATH", "")
-
-	// Variables numéricas opcionales
-	if threshold := getEnvOrDefault("MIN_SCORE_THRESHOLD", ""); threshold != "" {
-		if f, err := strconv.ParseFloat(threshold, 64); err == nil {
-			config.MinScoreThreshold = f
-		}
-	}
+// chatBaseURLs son las URLs por defecto de cada tipo de proveedor
+var chatBaseURLs = map[string]string{
+	"openai":    "https://api.openai.com/v1",
+	"anthropic": "https://api.anthropic.com",
+}
 
-	if dimension := getEnvOrDefault("EMBEDDING_DIMENSION", ""); dimension != "" {
-		if i, err := strconv.Atoi(dimension); err == nil {
-			config.EmbeddingDimension = i
+// applyChatDefaults completa los proveedores con la URL de su tipo y con las API keys
+// y el modelo de nivel superior cuando no definen los propios
+func (c *Config) applyChatDefaults() {
+	for name, provider := range c.Chat.Providers {
+		if provider.BaseURL == "" {
+			provider.BaseURL = chatBaseURLs[provider.Type]
 		}
-	}
-
-	if maxTopK := getEnvOrDefault("MAX_TOP_K", ""); maxTopK != "" {
-		if i, err := strconv.Atoi(maxTopK); err == nil {
-			config.MaxTopK = i
+		switch provider.Type {
+		case "openai":
+			if provider.APIKey == "" && provider.BaseURL == chatBaseURLs["openai"] {
+				provider.APIKey = c.OpenAIAPIKey
+			}
+			if provider.Model == "" {
+				provider.Model = c.ChatModel
+			}
+		case "anthropic":
+			if provider.APIKey == "" {
+				provider.APIKey = c.AnthropicAPIKey
+			}
 		}
+		c.Chat.Providers[name] = provider
 	}
+}
 
-	if defaultTopK := getEnvOrDefault("DEFAULT_TOP_K", ""); defaultTopK != "" {
-		if i, err := strconv.Atoi(defaultTopK); err == nil {
-			config.DefaultTopK = i
-		}
-	}
+// GenerationConfig define los parámetros de generación. Models reemplaza por completo
+// a Default para los modelos listados.
+type GenerationConfig struct {
+	Default models.GenerationParams            `yaml:"default"`
+	Models  map[string]models.GenerationParams `yaml:"models"`
+}
 
-	if price := getEnvOrDefault("EMBEDDING_PRICE_PER_1K", ""); price != "" {
-		if f, err := strconv.ParseFloat(price, 64); err == nil {
-			config.EmbeddingPricePer1K = f
-		}
+// For retorna los parámetros de generación del modelo
+func (g GenerationConfig) For(model string) models.GenerationParams {
+	if params, ok := g.Models[model]; ok {
+		return params
 	}
+	return g.Default
+}
 
-	if chatPrice := getEnvOrDefault("CHAT_PRICE_PER_1K", ""); chatPrice != "" {
-		if f, err := strconv.ParseFloat(chatPrice, 64); err == nil {
-			config.ChatPricePer1K = f
-		}
-	}
+// AnswerStyles son los estilos de respuesta que reciben los templates de prompt
+var AnswerStyles = []string{"short", "detailed", "bullets"}
 
-	if err := config.validate(); err != nil {
-		return config, err
-	}
+// PromptsConfig define de dónde se cargan los templates de prompt y qué versión se usa.
+// La versión se elige por solicitud (prompt_version), si no por API key y si no DefaultVersion.
+type PromptsConfig struct {
+	Dir            string            `yaml:"dir"`
+	DefaultVersion string            `yaml:"default_version"`
+	PerAPIKey      map[string]string `yaml:"per_api_key"` // nombre del cliente -> versión
+}
+
+// SearchConfig define los valores por defecto y los límites de las opciones de búsqueda
+// que puede enviar el cliente. Los valores fuera de límite se rechazan; las opciones
+// deshabilitadas por el servidor se ignoran.
+type SearchConfig struct {
+	AnswersEnabled  bool     `yaml:"answers_enabled"`   // si es false nunca se genera respuesta
+	TextEnabled     bool     `yaml:"text_enabled"`      // si es false nunca se incluye el texto de los resultados
+	DefaultStyle    string   `yaml:"default_style"`     // short, detailed o bullets
+	AllowedStyles   []string `yaml:"allowed_styles"`    // subconjunto de short, detailed y bullets
+	MinScoreFloor   float64  `yaml:"min_score_floor"`   // mínimo aceptado para min_score
+	MaxAnswerTokens int      `yaml:"max_answer_tokens"` // máximo aceptado para max_answer_tokens
+	AnswerLanguages []string `yaml:"answer_languages"`  // idiomas aceptados para la respuesta
+
+	// Expansión de consultas con reformulaciones generadas por el modelo de chat
+	ExpansionEnabled  bool `yaml:"expansion_enabled"`  // si es false expand_query se ignora
+	DefaultExpansions int  `yaml:"default_expansions"` // reformulaciones por defecto
+	MaxExpansions     int  `yaml:"max_expansions"`     // máximo aceptado para expansions
+
+	// Modo HyDE: búsqueda con un fragmento hipotético generado por el modelo de chat
+	HyDEEnabled bool `yaml:"hyde_enabled"` // si es false retrieval_mode=hyde usa el modo estándar
+	HyDEAverage bool `yaml:"hyde_average"` // promediar con el embedding de la consulta por defecto
+}
 
-	log.Info(context.Background(), "Configuracion cargada correctamente", log.Any("conf", config))
+// SubtitlesConfig define las pistas de subtítulos. Cada video puede tener una pista por
+// idioma (subtitles.en.vtt); subtitles.vtt es la pista en DefaultLanguage.
+type SubtitlesConfig struct {
+	DefaultLanguage string `yaml:"default_language"` // idioma original de los videos
+}
 
-	return config, nil
+// TranscriptConfig define cómo se unen los cues en párrafos para GET /video/:id/transcript
+type TranscriptConfig struct {
+	ParagraphPauseSeconds float64 `yaml:"paragraph_pause_seconds"` // pausa entre cues que corta el párrafo
+	ParagraphSeconds      float64 `yaml:"paragraph_seconds"`       // duración desde la que se corta en un fin de oración
+}
+
+// TranslationConfig define cómo se traducen las pistas de subtítulos: los cues se envían
+// al modelo de chat en lotes, acompañados de los cues anteriores como contexto
+type TranslationConfig struct {
+	BatchSize     int `yaml:"batch_size"`      // cues traducidos por llamada
+	ContextCues   int `yaml:"context_cues"`    // cues anteriores enviados como contexto
+	MaxLineLength int `yaml:"max_line_length"` // caracteres por línea de la traducción
+	MaxLines      int `yaml:"max_lines"`       // líneas por cue
+}
+
+// SummaryConfig define cómo se generan los resúmenes de video: la transcripción se
+// divide en partes de ChunkTokens, se resume cada parte y luego se combinan los resúmenes.
+type SummaryConfig struct {
+	ChunkTokens      int  `yaml:"chunk_tokens"`       // tokens estimados por parte de la transcripción
+	Concurrency      int  `yaml:"concurrency"`        // partes resumidas en paralelo
+	MaxKeyPoints     int  `yaml:"max_key_points"`     // puntos clave del resumen final
+	GenerateOnDemand bool `yaml:"generate_on_demand"` // generar al pedir un resumen inexistente
+}
+
+// ChaptersConfig define cómo se detectan los capítulos: la transcripción se divide en
+// ventanas de WindowSeconds y se corta donde cambia el tema entre ventanas consecutivas.
+type ChaptersConfig struct {
+	WindowSeconds     int  `yaml:"window_seconds"`      // duración de cada ventana comparada
+	MinChapterSeconds int  `yaml:"min_chapter_seconds"` // duración mínima de un capítulo
+	MaxChapters       int  `yaml:"max_chapters"`
+	GenerateOnDemand  bool `yaml:"generate_on_demand"` // generar al pedir capítulos inexistentes
+}
+
+// HLSConfig define dónde están las renditions HLS locales: <videos_path>/<id>/<Dir>/,
+// con MasterPlaylist como punto de entrada
+type HLSConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	Dir            string `yaml:"dir"`
+	MasterPlaylist string `yaml:"master_playlist"`
+}
+
+// ThumbnailsConfig define las variantes de miniaturas. Las variantes se generan al pedirlas
+// y se guardan en CacheDir hasta que cambia la miniatura original.
+type ThumbnailsConfig struct {
+	CacheDir  string                   `yaml:"cache_dir"`
+	Presets   map[string]ThumbnailSize `yaml:"presets"` // tamaños de ?size=
+	MaxWidth  int                      `yaml:"max_width"`
+	MaxHeight int                      `yaml:"max_height"`
+	Quality   int                      `yaml:"quality"` // calidad JPEG, 1 a 100
+
+	// Los tamaños de ?width= y ?height= se redondean hacia arriba a un múltiplo de SizeStep,
+	// y de cada original se guardan a lo sumo MaxVariants variantes (se borran las más viejas)
+	SizeStep    int `yaml:"size_step"`
+	MaxVariants int `yaml:"max_variants"`
+
+	// Imagen (JPEG, PNG o SVG) servida si el video no tiene miniatura; vacío usa un SVG
+	// genérico. SourcePlaceholders la reemplaza según el campo source del video.
+	Placeholder        string            `yaml:"placeholder"`
+	SourcePlaceholders map[string]string `yaml:"source_placeholders"` // source -> imagen
+}
+
+// ThumbnailSize es un tamaño de miniatura. Si falta una dimensión se conserva la proporción.
+type ThumbnailSize struct {
+	Width  int `yaml:"width"`
+	Height int `yaml:"height"`
+}
+
+// CORSConfig define la política de CORS.
+// AllowedOrigins admite "*", orígenes exactos y subdominios con "https://*.ejemplo.com".
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers"`
+	ExposedHeaders   []string `yaml:"exposed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	MaxAgeSeconds    int      `yaml:"max_age_seconds"`
+}
+
+// LoggingConfig contiene la configuración de logs
+type LoggingConfig struct {
+	Level     string            `yaml:"level"`    // debug, info, warn o error
+	Encoding  string            `yaml:"encoding"` // json o console
+	Outputs   []string          `yaml:"outputs"`  // stdout, stderr o rutas de archivo
+	Rotation  LogRotationConfig `yaml:"rotation"`
+	Redaction RedactionConfig   `yaml:"redaction"`
+	Body      BodyLoggingConfig `yaml:"body"`
+}
+
+// LogRotationConfig define la rotación de los archivos de log por tamaño y antigüedad
+type LogRotationConfig struct {
+	MaxSizeMB  int  `yaml:"max_size_mb"`
+	MaxAgeDays int  `yaml:"max_age_days"`
+	MaxBackups int  `yaml:"max_backups"`
+	Compress   bool `yaml:"compress"`
+}
+
+// RedactionConfig agrega reglas de redacción a las reglas por defecto del logger
+type RedactionConfig struct {
+	MaskKeys []string `yaml:"mask_keys"`
+	HashKeys []string `yaml:"hash_keys"`
+	Patterns []string `yaml:"patterns"`
+}
+
+// BodyLoggingConfig define si se loggea el cuerpo de las respuestas.
+// Routes usa el patrón de la ruta (por ejemplo "/video/:id") y reemplaza a Default.
+type BodyLoggingConfig struct {
+	Default BodyLoggingRule            `yaml:"default"`
+	Routes  map[string]BodyLoggingRule `yaml:"routes"`
+}
+
+// BodyLoggingRule indica si se loggea el cuerpo, con qué probabilidad y hasta cuántos
+// bytes. Los cuerpos más grandes no se loggean, solo su tamaño.
+type BodyLoggingRule struct {
+	Enabled    bool    `yaml:"enabled"`
+	SampleRate float64 `yaml:"sample_rate"`
+	MaxBytes   int     `yaml:"max_bytes"`
+}
+
+// BudgetConfig contiene los presupuestos en USD, globales y por API key
+type BudgetConfig struct {
+	Global    BudgetLimits `yaml:"global"`
+	PerAPIKey BudgetLimits `yaml:"per_api_key"`
+}
+
+// BudgetLimits define límites blandos y duros diarios y mensuales (0 = sin límite).
+// Al alcanzar el límite blando las búsquedas no generan respuesta; al alcanzar el duro se rechazan.
+type BudgetLimits struct {
+	DailySoftUSD   float64 `yaml:"daily_soft_usd"`
+	DailyHardUSD   float64 `yaml:"daily_hard_usd"`
+	MonthlySoftUSD float64 `yaml:"monthly_soft_usd"`
+	MonthlyHardUSD float64 `yaml:"monthly_hard_usd"`
+}
+
+// RateLimitConfig contiene los límites por clase de ruta
+type RateLimitConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Search  RateLimitRule `yaml:"search"`  // Rutas costosas (embeddings + chat)
+	Media   RateLimitRule `yaml:"media"`   // Videos, subtítulos, miniaturas
+	Default RateLimitRule `yaml:"default"` // Resto de las rutas
+}
+
+// HTTPCacheConfig define el cache HTTP de cada tipo de recurso. Las respuestas llevan un
+// ETag con el hash del contenido; las URL con ?v=<hash> se cachean VersionedMaxAgeSeconds.
+type HTTPCacheConfig struct {
+	Subtitles              CachePolicy `yaml:"subtitles"`
+	Transcript             CachePolicy `yaml:"transcript"`
+	Summary                CachePolicy `yaml:"summary"`
+	Chapters               CachePolicy `yaml:"chapters"`
+	Thumbnails             CachePolicy `yaml:"thumbnails"`
+	Video                  CachePolicy `yaml:"video"`
+	HLSPlaylists           CachePolicy `yaml:"hls_playlists"`
+	HLSSegments            CachePolicy `yaml:"hls_segments"`
+	VersionedMaxAgeSeconds int         `yaml:"versioned_max_age_seconds"`
+}
+
+// CachePolicy define el Cache-Control de un tipo de recurso. Con MaxAgeSeconds 0 el
+// cliente revalida en cada uso (con If-None-Match recibe un 304 si no cambió).
+type CachePolicy struct {
+	MaxAgeSeconds int  `yaml:"max_age_seconds"`
+	Private       bool `yaml:"private"`  // solo el navegador, no caches compartidos
+	NoStore       bool `yaml:"no_store"` // no guardar la respuesta
+}
+
+// RateLimitRule define un token bucket: recarga por minuto y ráfaga máxima
+type RateLimitRule struct {
+	RequestsPerMinute float64 `yaml:"requests_per_minute"`
+	Burst             int     `yaml:"burst"`
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// defaults retorna la configuración base, antes de aplicar archivo y entorno
+func defaults() Config {
+	return Config{
+		EmbeddingDimension: 512,
+		MinScoreThreshold:  0.30,
+		MaxTopK:            50,
+		DefaultTopK:        10,
+		PricingFile:        "pricing.json",
+		Chat: ChatConfig{
+			Fallback:       []string{"openai"},
+			TimeoutSeconds: 30,
+			Providers: map[string]ChatProviderConfig{
+				"openai":    {Type: "openai"},
+				"anthropic": {Type: "anthropic", Model: "claude-3-5-haiku-latest"},
+				"echo":      {Type: "echo"},
+			},
+		},
+		Generation: GenerationConfig{
+			Default: models.GenerationParams{MaxTokens: 1000, Temperature: 0.7, ContextTokens: 3000},
+		},
+		Prompts: PromptsConfig{
+			Dir:            "prompts",
+			DefaultVersion: "v2",
+		},
+		Search: SearchConfig{
+			AnswersEnabled:  true,
+			TextEnabled:     true,
+			DefaultStyle:    "short",
+			AllowedStyles:   []string{"short", "detailed", "bullets"},
+			MinScoreFloor:   0.1,
+			MaxAnswerTokens: 2000,
+			AnswerLanguages: []string{"es", "en"},
+
+			ExpansionEnabled:  true,
+			DefaultExpansions: 3,
+			MaxExpansions:     5,
+
+			HyDEEnabled: true,
+			HyDEAverage: true,
+		},
+		Subtitles: SubtitlesConfig{
+			DefaultLanguage: "es",
+		},
+		Transcript: TranscriptConfig{
+			ParagraphPauseSeconds: 2,
+			ParagraphSeconds:      30,
+		},
+		Translation: TranslationConfig{
+			BatchSize:     30,
+			ContextCues:   5,
+			MaxLineLength: 42,
+			MaxLines:      2,
+		},
+		Summary: SummaryConfig{
+			ChunkTokens:      3000,
+			Concurrency:      3,
+			MaxKeyPoints:     8,
+			GenerateOnDemand: true,
+		},
+		Chapters: ChaptersConfig{
+			WindowSeconds:     60,
+			MinChapterSeconds: 120,
+			MaxChapters:       12,
+			GenerateOnDemand:  true,
+		},
+		HLS: HLSConfig{
+			Enabled:        true,
+			Dir:            "hls",
+			MasterPlaylist: "master.m3u8",
+		},
+		Thumbnails: ThumbnailsConfig{
+			CacheDir: "thumbnails_cache",
+			Presets: map[string]ThumbnailSize{
+				"small":  {Width: 160, Height: 90},
+				"medium": {Width: 320, Height: 180},
+				"large":  {Width: 640, Height: 360},
+			},
+			MaxWidth:    1920,
+			MaxHeight:   1080,
+			Quality:     85,
+			SizeStep:    40,
+			MaxVariants: 20,
+		},
+		Port: "8000",
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Search:  RateLimitRule{RequestsPerMinute: 10, Burst: 5},
+			Media:   RateLimitRule{RequestsPerMinute: 300, Burst: 60},
+			Default: RateLimitRule{RequestsPerMinute: 60, Burst: 20},
+		},
+		HTTPCache: HTTPCacheConfig{
+			Subtitles:              CachePolicy{MaxAgeSeconds: 300},
+			Transcript:             CachePolicy{MaxAgeSeconds: 300},
+			Summary:                CachePolicy{MaxAgeSeconds: 300},
+			Chapters:               CachePolicy{MaxAgeSeconds: 300},
+			Thumbnails:             CachePolicy{MaxAgeSeconds: 86400},
+			Video:                  CachePolicy{MaxAgeSeconds: 3600},
+			HLSPlaylists:           CachePolicy{MaxAgeSeconds: 60},
+			HLSSegments:            CachePolicy{MaxAgeSeconds: 86400},
+			VersionedMaxAgeSeconds: 31536000,
+		},
+		UsageLedgerPath: "usage.jsonl",
+		DefaultLanguage: "es",
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "POST", "PUT", "OPTIONS"},
+			AllowedHeaders: []string{"Content-Type", "Authorization", "X-API-Key", "Accept-Language", "If-None-Match"},
+			ExposedHeaders: []string{
+				"X-Request-ID", "Retry-After", "ETag",
+				"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+			},
+			MaxAgeSeconds: 600,
+		},
+		Logging: LoggingConfig{
+			Level:    "info",
+			Encoding: "console",
+			Outputs:  []string{"stdout", "transcribe-api.log"},
+			Rotation: LogRotationConfig{MaxSizeMB: 100, MaxAgeDays: 30, MaxBackups: 10},
+			Body: BodyLoggingConfig{
+				Default: BodyLoggingRule{Enabled: true, SampleRate: 1, MaxBytes: 1000},
+				// Las búsquedas contienen texto de usuarios y las rutas de media son binarias
+				Routes: map[string]BodyLoggingRule{
+					"/search":              {Enabled: false},
+					"/video/:id":           {Enabled: false},
+					"/video/:id/thumbnail": {Enabled: false},
+					"/video/:id/hls/*path": {Enabled: false},
+				},
+			},
+		},
 	}
-	return defaultValue
 }
 
-func (c *Config) validate() error {
-	if c.OpenAIAPIKey == "" {
-		return fmt.Errorf("OPENAI_API_KEY es requerida")
+// LoadConfig carga la configuración en capas: valores por defecto, archivo YAML,
+// archivo .env y variables de entorno (en ese orden de precedencia creciente).
+// Si path es vacío se usa CONFIG_FILE o config.yaml; ambos son opcionales.
+// Retorna todos los campos inválidos juntos en un único error.
+func LoadConfig(path string) (Config, error) {
+	config := defaults()
+
+	// .env es opcional: en producción las variables vienen del entorno
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return config, fmt.Errorf("no se pudo cargar archivo .env: %v", err)
 	}
 
-	if c.PineconeAPIKey == "" {
-		return fmt.Errorf("PINECONE_API_KEY es requerida")
+	required := path != ""
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+		required = path != ""
+	}
+	if path == "" {
+		path = DefaultConfigFile
 	}
 
-	if c.IndexName == "" {
-		return fmt.Errorf("INDEX_NAME es requerida")
+	if err := loadFile(path, &config, required); err != nil {
+		return config, err
 	}
 
-	if c.EmbeddingModel == "" {
-		return fmt.Errorf("EMBEDDING_MODEL es requerida")
+	var errs []error
+	errs = append(errs, applyEnv(&config)...)
+	config.applyChatDefaults()
+
+	if len(config.Pricing) == 0 {
+		pricing, err := loadPricing(config.PricingFile)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		config.Pricing = pricing
 	}
 
-	if c.ChatModel == "" {
-		return fmt.Errorf("CHAT_MODEL es requerida")
+	errs = append(errs, config.validate()...)
+	if len(errs) > 0 {
+		return config, fmt.Errorf("configuración inválida:\n%w", errors.Join(errs...))
 	}
 
-	if c.Port == "" {
-		return fmt.Errorf("PORT es requerida")
+	return config, nil
+}
+
+// loadFile aplica el archivo YAML sobre config. Los campos desconocidos son un error.
+func loadFile(path string, config *Config, required bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("no se pudo leer el archivo de configuración %s: %v", path, err)
 	}
 
-	if c.VideosPath == "" {
-		return fmt.Errorf("VIDEOS_PATH es requerida")
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("no se pudo parsear el archivo de configuración %s: %v", path, err)
 	}
 
-	if c.MinScoreThreshold < 0 || c.MinScoreThreshold > 1 {
-		return fmt.Errorf("MIN_SCORE_THRESHOLD debe estar entre 0 y 1")
+	return nil
+}
+
+// loadPricing carga la tabla de precios por modelo desde un archivo JSON
+func loadPricing(path string) (map[string]models.ModelPrice, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer la tabla de precios %s: %v", path, err)
 	}
 
-	if c.MaxTopK < 1 || c.DefaultTopK < 1 {
-		return fmt.Errorf("top_k debe ser mayor a 0")
+	var table struct {
+		Models map[string]models.ModelPrice `json:"models"`
+	}
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("no se pudo parsear la tabla de precios %s: %v", path, err)
 	}
 
-	return nil
+	return table.Models, nil
 }
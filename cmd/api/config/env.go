@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envBinding asocia una variable de entorno con el campo de Config que sobrescribe
+type envBinding struct {
+	name   string
+	target interface{}
+}
+
+func envBindings(c *Config) []envBinding {
+	return []envBinding{
+		{"OPENAI_API_KEY", &c.OpenAIAPIKey},
+		{"PINECONE_API_KEY", &c.PineconeAPIKey},
+		{"ANTHROPIC_API_KEY", &c.AnthropicAPIKey},
+		{"INDEX_NAME", &c.IndexName},
+		{"EMBEDDING_MODEL", &c.EmbeddingModel},
+		{"EMBEDDING_DIMENSION", &c.EmbeddingDimension},
+		{"CHAT_MODEL", &c.ChatModel},
+		{"CHAT_FALLBACK", &c.Chat.Fallback},
+		{"CHAT_TIMEOUT_SECONDS", &c.Chat.TimeoutSeconds},
+		{"GENERATION_MAX_TOKENS", &c.Generation.Default.MaxTokens},
+		{"GENERATION_TEMPERATURE", &c.Generation.Default.Temperature},
+		{"GENERATION_CONTEXT_TOKENS", &c.Generation.Default.ContextTokens},
+		{"PROMPTS_DIR", &c.Prompts.Dir},
+		{"PROMPT_VERSION", &c.Prompts.DefaultVersion},
+		{"SEARCH_ANSWERS_ENABLED", &c.Search.AnswersEnabled},
+		{"SEARCH_TEXT_ENABLED", &c.Search.TextEnabled},
+		{"SEARCH_DEFAULT_STYLE", &c.Search.DefaultStyle},
+		{"SEARCH_MIN_SCORE_FLOOR", &c.Search.MinScoreFloor},
+		{"SEARCH_MAX_ANSWER_TOKENS", &c.Search.MaxAnswerTokens},
+		{"SEARCH_EXPANSION_ENABLED", &c.Search.ExpansionEnabled},
+		{"SEARCH_DEFAULT_EXPANSIONS", &c.Search.DefaultExpansions},
+		{"SEARCH_MAX_EXPANSIONS", &c.Search.MaxExpansions},
+		{"SEARCH_HYDE_ENABLED", &c.Search.HyDEEnabled},
+		{"SEARCH_HYDE_AVERAGE", &c.Search.HyDEAverage},
+		{"SUBTITLES_DEFAULT_LANGUAGE", &c.Subtitles.DefaultLanguage},
+		{"TRANSCRIPT_PARAGRAPH_PAUSE_SECONDS", &c.Transcript.ParagraphPauseSeconds},
+		{"TRANSCRIPT_PARAGRAPH_SECONDS", &c.Transcript.ParagraphSeconds},
+		{"TRANSLATION_BATCH_SIZE", &c.Translation.BatchSize},
+		{"TRANSLATION_CONTEXT_CUES", &c.Translation.ContextCues},
+		{"TRANSLATION_MAX_LINE_LENGTH", &c.Translation.MaxLineLength},
+		{"TRANSLATION_MAX_LINES", &c.Translation.MaxLines},
+		{"SUMMARY_CHUNK_TOKENS", &c.Summary.ChunkTokens},
+		{"SUMMARY_CONCURRENCY", &c.Summary.Concurrency},
+		{"SUMMARY_MAX_KEY_POINTS", &c.Summary.MaxKeyPoints},
+		{"SUMMARY_GENERATE_ON_DEMAND", &c.Summary.GenerateOnDemand},
+		{"CHAPTERS_WINDOW_SECONDS", &c.Chapters.WindowSeconds},
+		{"CHAPTERS_MIN_CHAPTER_SECONDS", &c.Chapters.MinChapterSeconds},
+		{"CHAPTERS_MAX_CHAPTERS", &c.Chapters.MaxChapters},
+		{"CHAPTERS_GENERATE_ON_DEMAND", &c.Chapters.GenerateOnDemand},
+		{"HLS_ENABLED", &c.HLS.Enabled},
+		{"HLS_DIR", &c.HLS.Dir},
+		{"HLS_MASTER_PLAYLIST", &c.HLS.MasterPlaylist},
+		{"THUMBNAILS_CACHE_DIR", &c.Thumbnails.CacheDir},
+		{"THUMBNAILS_MAX_WIDTH", &c.Thumbnails.MaxWidth},
+		{"THUMBNAILS_MAX_HEIGHT", &c.Thumbnails.MaxHeight},
+		{"THUMBNAILS_QUALITY", &c.Thumbnails.Quality},
+		{"THUMBNAILS_SIZE_STEP", &c.Thumbnails.SizeStep},
+		{"THUMBNAILS_MAX_VARIANTS", &c.Thumbnails.MaxVariants},
+		{"THUMBNAILS_PLACEHOLDER", &c.Thumbnails.Placeholder},
+		{"MIN_SCORE_THRESHOLD", &c.MinScoreThreshold},
+		{"MAX_TOP_K", &c.MaxTopK},
+		{"DEFAULT_TOP_K", &c.DefaultTopK},
+		{"PRICING_FILE", &c.PricingFile},
+		{"PORT", &c.Port},
+		{"VIDEOS_PATH", &c.VideosPath},
+		{"API_KEYS", &c.APIKeys},
+		{"RATE_LIMIT_ENABLED", &c.RateLimit.Enabled},
+		{"RATE_LIMIT_SEARCH_PER_MINUTE", &c.RateLimit.Search.RequestsPerMinute},
+		{"RATE_LIMIT_SEARCH_BURST", &c.RateLimit.Search.Burst},
+		{"RATE_LIMIT_MEDIA_PER_MINUTE", &c.RateLimit.Media.RequestsPerMinute},
+		{"RATE_LIMIT_MEDIA_BURST", &c.RateLimit.Media.Burst},
+		{"RATE_LIMIT_DEFAULT_PER_MINUTE", &c.RateLimit.Default.RequestsPerMinute},
+		{"RATE_LIMIT_DEFAULT_BURST", &c.RateLimit.Default.Burst},
+		{"HTTP_CACHE_SUBTITLES_MAX_AGE_SECONDS", &c.HTTPCache.Subtitles.MaxAgeSeconds},
+		{"HTTP_CACHE_TRANSCRIPT_MAX_AGE_SECONDS", &c.HTTPCache.Transcript.MaxAgeSeconds},
+		{"HTTP_CACHE_SUMMARY_MAX_AGE_SECONDS", &c.HTTPCache.Summary.MaxAgeSeconds},
+		{"HTTP_CACHE_CHAPTERS_MAX_AGE_SECONDS", &c.HTTPCache.Chapters.MaxAgeSeconds},
+		{"HTTP_CACHE_THUMBNAILS_MAX_AGE_SECONDS", &c.HTTPCache.Thumbnails.MaxAgeSeconds},
+		{"HTTP_CACHE_VIDEO_MAX_AGE_SECONDS", &c.HTTPCache.Video.MaxAgeSeconds},
+		{"HTTP_CACHE_HLS_PLAYLISTS_MAX_AGE_SECONDS", &c.HTTPCache.HLSPlaylists.MaxAgeSeconds},
+		{"HTTP_CACHE_HLS_SEGMENTS_MAX_AGE_SECONDS", &c.HTTPCache.HLSSegments.MaxAgeSeconds},
+		{"HTTP_CACHE_VERSIONED_MAX_AGE_SECONDS", &c.HTTPCache.VersionedMaxAgeSeconds},
+		{"BUDGET_DAILY_SOFT_USD", &c.Budget.Global.DailySoftUSD},
+		{"BUDGET_DAILY_HARD_USD", &c.Budget.Global.DailyHardUSD},
+		{"BUDGET_MONTHLY_SOFT_USD", &c.Budget.Global.MonthlySoftUSD},
+		{"BUDGET_MONTHLY_HARD_USD", &c.Budget.Global.MonthlyHardUSD},
+		{"BUDGET_KEY_DAILY_SOFT_USD", &c.Budget.PerAPIKey.DailySoftUSD},
+		{"BUDGET_KEY_DAILY_HARD_USD", &c.Budget.PerAPIKey.DailyHardUSD},
+		{"BUDGET_KEY_MONTHLY_SOFT_USD", &c.Budget.PerAPIKey.MonthlySoftUSD},
+		{"BUDGET_KEY_MONTHLY_HARD_USD", &c.Budget.PerAPIKey.MonthlyHardUSD},
+		{"USAGE_LEDGER_PATH", &c.UsageLedgerPath},
+		{"ADMIN_API_KEY", &c.AdminAPIKey},
+		{"DEFAULT_LANGUAGE", &c.DefaultLanguage},
+		{"CORS_ALLOWED_ORIGINS", &c.CORS.AllowedOrigins},
+		{"CORS_ALLOW_CREDENTIALS", &c.CORS.AllowCredentials},
+		{"CORS_MAX_AGE_SECONDS", &c.CORS.MaxAgeSeconds},
+		{"LOG_LEVEL", &c.Logging.Level},
+		{"LOG_ENCODING", &c.Logging.Encoding},
+		{"LOG_OUTPUTS", &c.Logging.Outputs},
+		{"LOG_MAX_SIZE_MB", &c.Logging.Rotation.MaxSizeMB},
+		{"LOG_MAX_AGE_DAYS", &c.Logging.Rotation.MaxAgeDays},
+		{"LOG_MAX_BACKUPS", &c.Logging.Rotation.MaxBackups},
+		{"LOG_BODY_ENABLED", &c.Logging.Body.Default.Enabled},
+		{"LOG_BODY_SAMPLE_RATE", &c.Logging.Body.Default.SampleRate},
+		{"LOG_BODY_MAX_BYTES", &c.Logging.Body.Default.MaxBytes},
+	}
+}
+
+// applyEnv sobrescribe la configuración con las variables de entorno definidas.
+// Un valor que no se puede parsear es un error, no se ignora.
+func applyEnv(c *Config) []error {
+	var errs []error
+
+	for _, binding := range envBindings(c) {
+		value, ok := os.LookupEnv(binding.name)
+		if !ok || value == "" {
+			continue
+		}
+
+		if err := setValue(binding.target, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", binding.name, err))
+		}
+	}
+
+	return errs
+}
+
+func setValue(target interface{}, value string) error {
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("se esperaba un entero, se recibió %q", value)
+		}
+		*t = i
+	case *float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("se esperaba un número, se recibió %q", value)
+		}
+		*t = f
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("se esperaba true o false, se recibió %q", value)
+		}
+		*t = b
+	case *[]string:
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*t = list
+	case *map[string]string:
+		m, err := parseKeyValueList(value)
+		if err != nil {
+			return err
+		}
+		*t = m
+	default:
+		return fmt.Errorf("tipo de campo no soportado %T", target)
+	}
+	return nil
+}
+
+// parseKeyValueList parsea una lista "clave:valor,clave2:valor2"
+func parseKeyValueList(raw string) (map[string]string, error) {
+	m := make(map[string]string)
+	for i, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, ":")
+		if !ok || key == "" || value == "" {
+			// No se incluye el valor: puede contener secretos
+			return nil, fmt.Errorf("la entrada %d no tiene el formato clave:valor", i+1)
+		}
+		m[key] = value
+	}
+	return m, nil
+}
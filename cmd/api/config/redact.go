@@ -0,0 +1,51 @@
+package config
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue reemplaza a los secretos cuando la configuración se loggea o se imprime
+const redactedValue = "[REDACTED]"
+
+// Redacted retorna una copia de la configuración con los secretos ocultos.
+// Es la única forma en que la configuración debe loggearse.
+func (c Config) Redacted() Config {
+	r := c
+	r.OpenAIAPIKey = redact(c.OpenAIAPIKey)
+	r.PineconeAPIKey = redact(c.PineconeAPIKey)
+	r.AnthropicAPIKey = redact(c.AnthropicAPIKey)
+	r.AdminAPIKey = redact(c.AdminAPIKey)
+
+	// Los headers de los proveedores suelen llevar credenciales
+	r.Chat.Providers = make(map[string]ChatProviderConfig, len(c.Chat.Providers))
+	for name, provider := range c.Chat.Providers {
+		provider.APIKey = redact(provider.APIKey)
+		headers := make(map[string]string, len(provider.Headers))
+		for header, value := range provider.Headers {
+			headers[header] = redact(value)
+		}
+		if len(headers) > 0 {
+			provider.Headers = headers
+		}
+		r.Chat.Providers[name] = provider
+	}
+
+	r.APIKeys = make(map[string]string, len(c.APIKeys))
+	for name, key := range c.APIKeys {
+		r.APIKeys[name] = redact(key)
+	}
+
+	return r
+}
+
+// Dump retorna la configuración efectiva en YAML, con los secretos ocultos
+func (c Config) Dump() ([]byte, error) {
+	return yaml.Marshal(c.Redacted())
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
@@ -0,0 +1,403 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// subtitleLanguagePattern es el formato de los idiomas de las pistas de subtítulos ("es", "en")
+var subtitleLanguagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// thumbnailExtensions son los formatos aceptados para los placeholders de miniaturas
+var thumbnailExtensions = []string{".jpg", ".jpeg", ".png", ".svg"}
+
+// validate retorna todos los campos inválidos de la configuración
+func (c *Config) validate() []error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	required := map[string]string{
+		"openai_api_key (OPENAI_API_KEY)":     c.OpenAIAPIKey,
+		"pinecone_api_key (PINECONE_API_KEY)": c.PineconeAPIKey,
+		"index_name (INDEX_NAME)":             c.IndexName,
+		"embedding_model (EMBEDDING_MODEL)":   c.EmbeddingModel,
+		"port (PORT)":                         c.Port,
+		"videos_path (VIDEOS_PATH)":           c.VideosPath,
+		"usage_ledger_path":                   c.UsageLedgerPath,
+	}
+	for _, field := range sortedKeys(required) {
+		if required[field] == "" {
+			invalid("%s es requerida", field)
+		}
+	}
+
+	if c.EmbeddingDimension < 1 {
+		invalid("embedding_dimension debe ser mayor a 0")
+	}
+
+	if c.MinScoreThreshold < 0 || c.MinScoreThreshold > 1 {
+		invalid("min_score_threshold debe estar entre 0 y 1")
+	}
+
+	if c.MaxTopK < 1 {
+		invalid("max_top_k debe ser mayor a 0")
+	}
+	if c.DefaultTopK < 1 || c.DefaultTopK > c.MaxTopK {
+		invalid("default_top_k debe estar entre 1 y max_top_k")
+	}
+
+	if _, ok := c.Pricing[c.EmbeddingModel]; c.EmbeddingModel != "" && !ok {
+		invalid("el modelo %s no tiene precio configurado", c.EmbeddingModel)
+	}
+
+	if len(c.Chat.Fallback) == 0 {
+		invalid("chat.fallback requiere al menos un proveedor")
+	}
+	if c.Chat.TimeoutSeconds < 1 {
+		invalid("chat.timeout_seconds debe ser mayor a 0")
+	}
+	for _, name := range c.Chat.Fallback {
+		provider, ok := c.Chat.Providers[name]
+		if !ok {
+			invalid("chat.fallback: el proveedor %s no está definido en chat.providers", name)
+			continue
+		}
+		errs = append(errs, provider.validate("chat.providers."+name, c.Pricing)...)
+	}
+	for _, model := range sortedKeys(c.Pricing) {
+		price := c.Pricing[model]
+		if price.InputPer1K < 0 || price.OutputPer1K < 0 || price.CachedInputPer1K < 0 {
+			invalid("pricing.%s: los precios no pueden ser negativos", model)
+		}
+	}
+
+	for _, name := range sortedKeys(c.APIKeys) {
+		if c.APIKeys[name] == "" {
+			invalid("api_keys.%s no puede estar vacía", name)
+		}
+	}
+
+	errs = append(errs, validateGeneration("generation.default", c.Generation.Default)...)
+	for _, model := range sortedKeys(c.Generation.Models) {
+		errs = append(errs, validateGeneration("generation.models."+model, c.Generation.Models[model])...)
+	}
+
+	if len(c.Search.AllowedStyles) == 0 {
+		invalid("search.allowed_styles requiere al menos un estilo")
+	}
+	for _, style := range c.Search.AllowedStyles {
+		if !slices.Contains(AnswerStyles, style) {
+			invalid("search.allowed_styles: %s no es uno de: %s", style, strings.Join(AnswerStyles, ", "))
+		}
+	}
+	if !slices.Contains(c.Search.AllowedStyles, c.Search.DefaultStyle) {
+		invalid("search.default_style debe estar en search.allowed_styles")
+	}
+	if c.Search.MinScoreFloor < 0 || c.Search.MinScoreFloor > c.MinScoreThreshold {
+		invalid("search.min_score_floor debe estar entre 0 y min_score_threshold")
+	}
+	if c.Search.MaxAnswerTokens < 1 {
+		invalid("search.max_answer_tokens debe ser mayor a 0")
+	}
+	if c.Search.MaxExpansions < 1 {
+		invalid("search.max_expansions debe ser mayor a 0")
+	}
+	if c.Search.DefaultExpansions < 1 || c.Search.DefaultExpansions > c.Search.MaxExpansions {
+		invalid("search.default_expansions debe estar entre 1 y search.max_expansions")
+	}
+	if len(c.Search.AnswerLanguages) == 0 {
+		invalid("search.answer_languages requiere al menos un idioma")
+	}
+	for _, lang := range c.Search.AnswerLanguages {
+		if !i18n.Supported(lang) {
+			invalid("search.answer_languages: idioma no soportado %s (valores posibles: %s)", lang, strings.Join(i18n.Languages(), ", "))
+		}
+	}
+
+	if !subtitleLanguagePattern.MatchString(c.Subtitles.DefaultLanguage) {
+		invalid("subtitles.default_language debe ser un código de idioma de 2 o 3 letras minúsculas")
+	}
+
+	if c.Transcript.ParagraphPauseSeconds <= 0 {
+		invalid("transcript.paragraph_pause_seconds debe ser mayor a 0")
+	}
+	if c.Transcript.ParagraphSeconds < 5 {
+		invalid("transcript.paragraph_seconds debe ser al menos 5")
+	}
+
+	if c.Translation.BatchSize < 1 || c.Translation.BatchSize > 200 {
+		invalid("translation.batch_size debe estar entre 1 y 200")
+	}
+	if c.Translation.ContextCues < 0 {
+		invalid("translation.context_cues no puede ser negativo")
+	}
+	if c.Translation.MaxLineLength < 10 {
+		invalid("translation.max_line_length debe ser al menos 10")
+	}
+	if c.Translation.MaxLines < 1 {
+		invalid("translation.max_lines debe ser mayor a 0")
+	}
+
+	if c.Summary.ChunkTokens < 100 {
+		invalid("summary.chunk_tokens debe ser al menos 100")
+	}
+	if c.Summary.Concurrency < 1 {
+		invalid("summary.concurrency debe ser mayor a 0")
+	}
+	if c.Summary.MaxKeyPoints < 1 {
+		invalid("summary.max_key_points debe ser mayor a 0")
+	}
+	if c.Chapters.WindowSeconds < 10 {
+		invalid("chapters.window_seconds debe ser al menos 10")
+	}
+	if c.Chapters.MinChapterSeconds < c.Chapters.WindowSeconds {
+		invalid("chapters.min_chapter_seconds debe ser al menos chapters.window_seconds")
+	}
+	if c.Chapters.MaxChapters < 1 {
+		invalid("chapters.max_chapters debe ser mayor a 0")
+	}
+
+	if c.HLS.Enabled {
+		if c.HLS.Dir == "" || !filepath.IsLocal(c.HLS.Dir) {
+			invalid("hls.dir debe ser una ruta relativa a la carpeta del video")
+		}
+		if c.HLS.MasterPlaylist == "" || !filepath.IsLocal(c.HLS.MasterPlaylist) || filepath.Ext(c.HLS.MasterPlaylist) != ".m3u8" {
+			invalid("hls.master_playlist debe ser un archivo .m3u8 dentro de hls.dir")
+		}
+	}
+
+	if c.Thumbnails.CacheDir == "" {
+		invalid("thumbnails.cache_dir es requerido")
+	}
+	if c.Thumbnails.MaxWidth < 16 || c.Thumbnails.MaxHeight < 16 {
+		invalid("thumbnails.max_width y thumbnails.max_height deben ser al menos 16")
+	}
+	if c.Thumbnails.Quality < 1 || c.Thumbnails.Quality > 100 {
+		invalid("thumbnails.quality debe estar entre 1 y 100")
+	}
+	if c.Thumbnails.SizeStep < 1 {
+		invalid("thumbnails.size_step debe ser mayor a 0")
+	}
+	if c.Thumbnails.MaxVariants < 1 {
+		invalid("thumbnails.max_variants debe ser mayor a 0")
+	}
+	for _, name := range sortedKeys(c.Thumbnails.Presets) {
+		size := c.Thumbnails.Presets[name]
+		if size.Width < 0 || size.Width > c.Thumbnails.MaxWidth || size.Height < 0 || size.Height > c.Thumbnails.MaxHeight {
+			invalid("thumbnails.presets.%s debe estar dentro de max_width y max_height", name)
+		}
+		if size.Width == 0 && size.Height == 0 {
+			invalid("thumbnails.presets.%s requiere width o height", name)
+		}
+	}
+	placeholders := map[string]string{"thumbnails.placeholder": c.Thumbnails.Placeholder}
+	for source, path := range c.Thumbnails.SourcePlaceholders {
+		placeholders[fmt.Sprintf("thumbnails.source_placeholders[%q]", source)] = path
+	}
+	for _, field := range sortedKeys(placeholders) {
+		path := placeholders[field]
+		if path == "" {
+			continue
+		}
+		if !slices.Contains(thumbnailExtensions, strings.ToLower(filepath.Ext(path))) {
+			invalid("%s debe ser una imagen %s", field, strings.Join(thumbnailExtensions, ", "))
+		} else if _, err := os.Stat(path); err != nil {
+			invalid("%s: %v", field, err)
+		}
+	}
+
+	if c.Prompts.Dir == "" {
+		invalid("prompts.dir es requerido")
+	}
+	if c.Prompts.DefaultVersion == "" {
+		invalid("prompts.default_version es requerida")
+	}
+	for _, name := range sortedKeys(c.Prompts.PerAPIKey) {
+		if _, ok := c.APIKeys[name]; !ok {
+			invalid("prompts.per_api_key.%s no corresponde a ningún cliente de api_keys", name)
+		}
+	}
+
+	if c.RateLimit.Enabled {
+		rules := map[string]RateLimitRule{
+			"search":  c.RateLimit.Search,
+			"media":   c.RateLimit.Media,
+			"default": c.RateLimit.Default,
+		}
+		for _, class := range sortedKeys(rules) {
+			rule := rules[class]
+			if rule.RequestsPerMinute <= 0 {
+				invalid("rate_limit.%s.requests_per_minute debe ser mayor a 0", class)
+			}
+			if rule.Burst < 1 {
+				invalid("rate_limit.%s.burst debe ser mayor a 0", class)
+			}
+		}
+	}
+
+	policies := map[string]CachePolicy{
+		"subtitles":     c.HTTPCache.Subtitles,
+		"transcript":    c.HTTPCache.Transcript,
+		"summary":       c.HTTPCache.Summary,
+		"chapters":      c.HTTPCache.Chapters,
+		"thumbnails":    c.HTTPCache.Thumbnails,
+		"video":         c.HTTPCache.Video,
+		"hls_playlists": c.HTTPCache.HLSPlaylists,
+		"hls_segments":  c.HTTPCache.HLSSegments,
+	}
+	for _, asset := range sortedKeys(policies) {
+		if policies[asset].MaxAgeSeconds < 0 {
+			invalid("http_cache.%s.max_age_seconds no puede ser negativo", asset)
+		}
+	}
+	if c.HTTPCache.VersionedMaxAgeSeconds < 0 {
+		invalid("http_cache.versioned_max_age_seconds no puede ser negativo")
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		invalid("cors.allowed_origins requiere al menos un origen")
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" && c.CORS.AllowCredentials {
+			invalid("cors.allow_credentials no se puede usar con el origen \"*\"")
+		}
+		if origin != "*" && !strings.Contains(origin, "://") {
+			invalid("cors.allowed_origins: %q debe incluir el esquema (https://...)", origin)
+		}
+		if strings.Contains(origin, "*") && origin != "*" && !strings.Contains(origin, "://*.") {
+			invalid("cors.allowed_origins: %q solo admite comodín de subdominio (https://*.ejemplo.com)", origin)
+		}
+	}
+	if c.CORS.MaxAgeSeconds < 0 {
+		invalid("cors.max_age_seconds no puede ser negativo")
+	}
+
+	if !i18n.Supported(c.DefaultLanguage) {
+		invalid("default_language debe ser uno de: %s", strings.Join(i18n.Languages(), ", "))
+	}
+
+	switch c.Logging.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		invalid("logging.level debe ser debug, info, warn o error")
+	}
+	if c.Logging.Encoding != "json" && c.Logging.Encoding != "console" {
+		invalid("logging.encoding debe ser json o console")
+	}
+	if len(c.Logging.Outputs) == 0 {
+		invalid("logging.outputs requiere al menos una salida")
+	}
+	if c.Logging.Rotation.MaxSizeMB < 0 || c.Logging.Rotation.MaxAgeDays < 0 || c.Logging.Rotation.MaxBackups < 0 {
+		invalid("logging.rotation no admite valores negativos")
+	}
+
+	for _, pattern := range c.Logging.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			invalid("logging.redaction.patterns: patrón inválido %q: %v", pattern, err)
+		}
+	}
+
+	errs = append(errs, c.Logging.Body.Default.validate("logging.body.default")...)
+	for _, route := range sortedKeys(c.Logging.Body.Routes) {
+		errs = append(errs, c.Logging.Body.Routes[route].validate("logging.body.routes."+route)...)
+	}
+
+	errs = append(errs, c.Budget.Global.validate("budget.global")...)
+	errs = append(errs, c.Budget.PerAPIKey.validate("budget.per_api_key")...)
+
+	return errs
+}
+
+func (r BodyLoggingRule) validate(prefix string) []error {
+	var errs []error
+	if r.SampleRate < 0 || r.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("%s.sample_rate debe estar entre 0 y 1", prefix))
+	}
+	if r.MaxBytes < 0 {
+		errs = append(errs, fmt.Errorf("%s.max_bytes no puede ser negativo", prefix))
+	}
+	return errs
+}
+
+func (l BudgetLimits) validate(prefix string) []error {
+	var errs []error
+
+	values := map[string]float64{
+		"daily_soft_usd":   l.DailySoftUSD,
+		"daily_hard_usd":   l.DailyHardUSD,
+		"monthly_soft_usd": l.MonthlySoftUSD,
+		"monthly_hard_usd": l.MonthlyHardUSD,
+	}
+	for _, field := range sortedKeys(values) {
+		if values[field] < 0 {
+			errs = append(errs, fmt.Errorf("%s.%s no puede ser negativo", prefix, field))
+		}
+	}
+
+	if l.DailyHardUSD > 0 && l.DailySoftUSD > l.DailyHardUSD {
+		errs = append(errs, fmt.Errorf("%s.daily_soft_usd no puede superar a daily_hard_usd", prefix))
+	}
+	if l.MonthlyHardUSD > 0 && l.MonthlySoftUSD > l.MonthlyHardUSD {
+		errs = append(errs, fmt.Errorf("%s.monthly_soft_usd no puede superar a monthly_hard_usd", prefix))
+	}
+
+	return errs
+}
+
+func (p ChatProviderConfig) validate(prefix string, pricing map[string]models.ModelPrice) []error {
+	var errs []error
+	switch p.Type {
+	case "echo":
+		return nil
+	case "openai", "anthropic":
+	default:
+		return append(errs, fmt.Errorf("%s.type debe ser openai, anthropic o echo", prefix))
+	}
+
+	if p.BaseURL == "" {
+		errs = append(errs, fmt.Errorf("%s.base_url es requerida", prefix))
+	}
+	if p.Model == "" {
+		errs = append(errs, fmt.Errorf("%s.model es requerido (o chat_model para el tipo openai)", prefix))
+	} else if _, ok := pricing[p.Model]; !ok {
+		errs = append(errs, fmt.Errorf("%s: el modelo %s no tiene precio configurado", prefix, p.Model))
+	}
+	if p.Type == "anthropic" && p.APIKey == "" {
+		errs = append(errs, fmt.Errorf("%s.api_key es requerida (o ANTHROPIC_API_KEY)", prefix))
+	}
+	return errs
+}
+
+// validateGeneration valida los parámetros de generación de un modelo
+func validateGeneration(field string, params models.GenerationParams) []error {
+	var errs []error
+	if params.MaxTokens < 1 {
+		errs = append(errs, fmt.Errorf("%s.max_tokens debe ser mayor a 0", field))
+	}
+	if params.Temperature < 0 || params.Temperature > 2 {
+		errs = append(errs, fmt.Errorf("%s.temperature debe estar entre 0 y 2", field))
+	}
+	if params.ContextTokens < 1 {
+		errs = append(errs, fmt.Errorf("%s.context_tokens debe ser mayor a 0", field))
+	}
+	return errs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,6 +1,9 @@
 package dependencies
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
 )
@@ -8,6 +11,9 @@ import (
 type Dependencies struct {
 	PineconeService *services.PineconeService
 	OpenAIService   *services.OpenAIService
+	ChatModel       services.ChatModel
+	UsageLedger     *services.UsageLedger
+	PromptStore     *services.PromptStore
 }
 
 func NewDependencies(cfg config.Config) (Dependencies, error) {
@@ -16,15 +22,19 @@ func NewDependencies(cfg config.Config) (Dependencies, error) {
 	openAIService, err := services.NewOpenAIService(
 		cfg.OpenAIAPIKey,
 		cfg.EmbeddingModel,
-		cfg.EmbeddingPricePer1K,
-		cfg.ChatModel,
-		cfg.ChatPricePer1K,
+		services.Pricing(cfg.Pricing),
 	)
 	if err != nil {
 		return deps, err
 	}
 	deps.OpenAIService = openAIService
 
+	chatModel, err := newChatModel(cfg)
+	if err != nil {
+		return deps, err
+	}
+	deps.ChatModel = chatModel
+
 	pineconeService, err := services.NewPineconeService(
 		cfg.PineconeAPIKey,
 		cfg.IndexName,
@@ -34,5 +44,54 @@ func NewDependencies(cfg config.Config) (Dependencies, error) {
 	}
 	deps.PineconeService = pineconeService
 
+	usageLedger, err := services.NewUsageLedger(cfg.UsageLedgerPath)
+	if err != nil {
+		return deps, err
+	}
+	deps.UsageLedger = usageLedger
+
+	versions := []string{cfg.Prompts.DefaultVersion}
+	for _, version := range cfg.Prompts.PerAPIKey {
+		versions = append(versions, version)
+	}
+	promptStore, err := services.NewPromptStore(cfg.Prompts.Dir, versions)
+	if err != nil {
+		return deps, err
+	}
+	deps.PromptStore = promptStore
+
 	return deps, nil
 }
+
+// newChatModel crea los proveedores de chat en el orden de fallback configurado
+func newChatModel(cfg config.Config) (services.ChatModel, error) {
+	pricing := services.Pricing(cfg.Pricing)
+
+	providers := make([]services.ChatModel, 0, len(cfg.Chat.Fallback))
+	for _, name := range cfg.Chat.Fallback {
+		provider, ok := cfg.Chat.Providers[name]
+		if !ok {
+			return nil, fmt.Errorf("chat provider %s not configured", name)
+		}
+		params := cfg.Generation.For(provider.Model)
+
+		var chatModel services.ChatModel
+		var err error
+		switch provider.Type {
+		case services.ChatProviderOpenAI:
+			chatModel, err = services.NewOpenAIChat(name, provider.BaseURL, provider.APIKey, provider.Model, provider.Headers, params, pricing)
+		case services.ChatProviderAnthropic:
+			chatModel, err = services.NewAnthropicChat(name, provider.BaseURL, provider.APIKey, provider.Model, provider.Headers, params, pricing)
+		case services.ChatProviderEcho:
+			chatModel = services.NewEchoChat(name)
+		default:
+			err = fmt.Errorf("unknown chat provider type %s", provider.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, chatModel)
+	}
+
+	return services.NewFallbackChat(providers, time.Duration(cfg.Chat.TimeoutSeconds)*time.Second)
+}
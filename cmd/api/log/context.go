@@ -23,6 +23,12 @@ func With(ctx context.Context, fields ...Field) context.Context {
 	return context.WithValue(ctx, logCtxKey{}, logger)
 }
 
+// EnableDebug retorna un contexto cuyo logger registra también el nivel debug,
+// sin importar el nivel configurado
+func EnableDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, logCtxKey{}, getLogger(ctx).Debugging())
+}
+
 func Info(ctx context.Context, msg string, fields ...Field) {
 	getLogger(ctx).Info(msg, fields...)
 }
@@ -2,6 +2,7 @@ package log
 
 type Logger interface {
 	With(fields ...Field) Logger
+	Debugging() Logger
 	Info(msg string, fields ...Field)
 	Warn(msg string, fields ...Field)
 	Panic(msg string, fields ...Field)
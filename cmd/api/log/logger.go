@@ -1,41 +1,123 @@
 package log
 
 import (
+	"fmt"
+	"os"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var DefaultLogger Logger = &logger{
 	Logger: zap.NewNop(),
+	debug:  zap.NewNop(),
 }
 
 type logger struct {
 	*zap.Logger
+	debug *zap.Logger // mismo core sin filtro de nivel, para debug por request
 }
 
 var _ Logger = (*logger)(nil)
 
-func Initialize() Logger {
-	config := zap.NewDevelopmentConfig()
-	config.OutputPaths = []string{"stdout", "transcribe-api.log"}
-	config.ErrorOutputPaths = []string{"stderr", "transcribe-api.log"}
+// Options configura el logger de la aplicación
+type Options struct {
+	Level    string   // debug, info, warn o error
+	Encoding string   // json o console
+	Outputs  []string // stdout, stderr o rutas de archivo (con rotación)
+	Rotation Rotation
+	Redactor *Redactor
+}
+
+// Rotation define la rotación de los archivos de log
+type Rotation struct {
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+	Compress   bool
+}
 
-	l, err := config.Build()
+// Initialize construye el logger de la aplicación. Todas las entradas pasan por el
+// redactor antes de escribirse. Si la configuración es inválida se loggea por stderr.
+func Initialize(opts Options) Logger {
+	l, err := build(opts)
 	if err != nil {
-		// Si hay error, usar el logger por defecto
-		return &logger{
-			Logger: zap.NewNop(),
-		}
+		fmt.Fprintf(os.Stderr, "error inicializando logger, usando stderr: %v\n", err)
+		l, _ = build(Options{Level: "info", Outputs: []string{"stderr"}, Redactor: opts.Redactor})
 	}
+	return l
+}
+
+func build(opts Options) (*logger, error) {
+	level, err := zapcore.ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoder zapcore.Encoder
+	switch opts.Encoding {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	case "console", "":
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	default:
+		return nil, fmt.Errorf("encoding de logs inválido: %s", opts.Encoding)
+	}
+
+	writers := make([]zapcore.WriteSyncer, 0, len(opts.Outputs))
+	for _, output := range opts.Outputs {
+		writers = append(writers, writerFor(output, opts.Rotation))
+	}
+
+	// El core acepta debug; el nivel configurado se aplica en el logger raíz
+	// para poder habilitar debug en solicitudes puntuales
+	var core zapcore.Core = zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(writers...), zapcore.DebugLevel)
+	if opts.Redactor != nil {
+		core = newRedactingCore(core, opts.Redactor)
+	}
+
+	debug := zap.New(core,
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+	)
 
 	return &logger{
-		Logger: l,
+		Logger: debug.WithOptions(zap.IncreaseLevel(level)),
+		debug:  debug,
+	}, nil
+}
+
+func writerFor(output string, rotation Rotation) zapcore.WriteSyncer {
+	switch output {
+	case "stdout":
+		return zapcore.Lock(os.Stdout)
+	case "stderr":
+		return zapcore.Lock(os.Stderr)
+	default:
+		return zapcore.AddSync(&lumberjack.Logger{
+			Filename:   output,
+			MaxSize:    rotation.MaxSizeMB,
+			MaxAge:     rotation.MaxAgeDays,
+			MaxBackups: rotation.MaxBackups,
+			Compress:   rotation.Compress,
+		})
 	}
 }
 
 func (l *logger) With(fields ...Field) Logger {
-	child := l.Logger.With(fields...)
 	return &logger{
-		Logger: child,
+		Logger: l.Logger.With(fields...),
+		debug:  l.debug.With(fields...),
+	}
+}
+
+// Debugging retorna un logger que registra también el nivel debug
+func (l *logger) Debugging() Logger {
+	return &logger{
+		Logger: l.debug,
+		debug:  l.debug,
 	}
 }
 
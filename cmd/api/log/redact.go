@@ -0,0 +1,196 @@
+package log
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue reemplaza a los valores enmascarados
+const redactedValue = "[REDACTED]"
+
+// RedactionRules define cómo se protegen los datos sensibles en los logs.
+// Las keys se comparan sin distinguir mayúsculas, guiones ni guiones bajos.
+type RedactionRules struct {
+	MaskKeys []string // keys cuyo valor se reemplaza por [REDACTED]
+	HashKeys []string // keys cuyo valor se reemplaza por un hash (permite correlacionar sin exponer)
+	Patterns []string // regex que se borran de cualquier texto (mensaje y valores)
+}
+
+// DefaultRedactionRules enmascara secretos conocidos, hashea las consultas de usuarios
+// y borra tokens de OpenAI/Pinecone, bearer tokens y emails
+func DefaultRedactionRules() RedactionRules {
+	return RedactionRules{
+		MaskKeys: []string{
+			"authorization", "x-api-key", "x-admin-key", "api_key", "api_keys",
+			"openai_api_key", "pinecone_api_key", "admin_api_key", "password", "secret",
+		},
+		HashKeys: []string{"query"},
+		Patterns: []string{
+			`sk-[A-Za-z0-9_-]{16,}`,
+			`pcsk_[A-Za-z0-9_]{16,}`,
+			`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`,
+			`(?i)x-(api|admin)-key:\s*\S+`,
+			`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+		},
+	}
+}
+
+// Redactor aplica las reglas de redacción a mensajes y campos
+type Redactor struct {
+	mask     map[string]bool
+	hash     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compila las reglas de redacción
+func NewRedactor(rules RedactionRules) (*Redactor, error) {
+	r := &Redactor{
+		mask: make(map[string]bool, len(rules.MaskKeys)),
+		hash: make(map[string]bool, len(rules.HashKeys)),
+	}
+	for _, key := range rules.MaskKeys {
+		r.mask[normalizeKey(key)] = true
+	}
+	for _, key := range rules.HashKeys {
+		r.hash[normalizeKey(key)] = true
+	}
+	for _, pattern := range rules.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("patrón de redacción inválido %q: %v", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "_", "")
+	return strings.ReplaceAll(key, "-", "")
+}
+
+// Scrub borra de un texto todo lo que coincida con los patrones
+func (r *Redactor) Scrub(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, redactedValue)
+	}
+	return s
+}
+
+// Hash retorna un hash corto y estable del valor
+func Hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+func (r *Redactor) fields(fields []Field) []Field {
+	redacted := make([]Field, len(fields))
+	for i, f := range fields {
+		redacted[i] = r.field(f)
+	}
+	return redacted
+}
+
+func (r *Redactor) field(f Field) Field {
+	key := normalizeKey(f.Key)
+	if r.mask[key] {
+		return zap.String(f.Key, redactedValue)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if r.hash[key] {
+			if f.String == "" {
+				return f
+			}
+			return zap.String(f.Key, Hash(f.String))
+		}
+		return zap.String(f.Key, r.Scrub(f.String))
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok {
+			return zap.String(f.Key, r.Scrub(err.Error()))
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			return zap.String(f.Key, r.Scrub(s.String()))
+		}
+	case zapcore.ReflectType:
+		// Estructuras y mapas se redactan recorriendo su representación JSON
+		data, err := json.Marshal(f.Interface)
+		if err != nil {
+			return f
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return f
+		}
+		return zap.Any(f.Key, r.value(key, value))
+	}
+
+	return f
+}
+
+// value redacta recursivamente un valor genérico decodificado de JSON
+func (r *Redactor) value(key string, v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			nk := normalizeKey(k)
+			if r.mask[nk] {
+				t[k] = redactedValue
+				continue
+			}
+			t[k] = r.value(nk, child)
+		}
+		return t
+	case []interface{}:
+		for i, child := range t {
+			t[i] = r.value(key, child)
+		}
+		return t
+	case string:
+		if r.hash[key] && t != "" {
+			return Hash(t)
+		}
+		return r.Scrub(t)
+	default:
+		return v
+	}
+}
+
+// redactingCore aplica el Redactor antes de escribir cada entrada
+type redactingCore struct {
+	zapcore.Core
+	redactor *Redactor
+}
+
+func newRedactingCore(core zapcore.Core, redactor *Redactor) zapcore.Core {
+	return &redactingCore{Core: core, redactor: redactor}
+}
+
+func (c *redactingCore) With(fields []Field) zapcore.Core {
+	return &redactingCore{
+		Core:     c.Core.With(c.redactor.fields(fields)),
+		redactor: c.redactor,
+	}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []Field) error {
+	entry.Message = c.redactor.Scrub(entry.Message)
+	return c.Core.Write(entry, c.redactor.fields(fields))
+}
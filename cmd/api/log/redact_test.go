@@ -0,0 +1,131 @@
+package log
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newTestRedactor(t *testing.T) *Redactor {
+	t.Helper()
+	r, err := NewRedactor(DefaultRedactionRules())
+	if err != nil {
+		t.Fatalf("NewRedactor: %v", err)
+	}
+	return r
+}
+
+func TestNewRedactorInvalidPattern(t *testing.T) {
+	if _, err := NewRedactor(RedactionRules{Patterns: []string{"("}}); err == nil {
+		t.Error("se esperaba error por patrón inválido")
+	}
+}
+
+func TestRedactorField(t *testing.T) {
+	r := newTestRedactor(t)
+
+	tests := []struct {
+		name  string
+		field Field
+		want  string
+	}{
+		{name: "mask api_key", field: zap.String("api_key", "secreto"), want: redactedValue},
+		{name: "mask X-API-Key normalizada", field: zap.String("X-API-Key", "secreto"), want: redactedValue},
+		{name: "mask ApiKey sin separadores", field: zap.String("ApiKey", "secreto"), want: redactedValue},
+		{name: "mask Authorization", field: zap.String("Authorization", "Bearer abc"), want: redactedValue},
+		{name: "mask no depende del tipo", field: zap.Int("password", 1234), want: redactedValue},
+		{name: "hash query", field: zap.String("query", "cómo instalar"), want: Hash("cómo instalar")},
+		{name: "hash Query normalizada", field: zap.String("Query", "cómo instalar"), want: Hash("cómo instalar")},
+		{name: "query vacía no se hashea", field: zap.String("query", ""), want: ""},
+		{name: "bearer token", field: zap.String("header", "token: Bearer abc.def-123"), want: "token: " + redactedValue},
+		{name: "email", field: zap.String("user", "contacto: ana.perez@example.com"), want: "contacto: " + redactedValue},
+		{name: "token de OpenAI", field: zap.String("msg", "usando sk-abcdefghijklmnop1234"), want: "usando " + redactedValue},
+		{name: "error", field: zap.Error(errors.New("falló para bob@example.org")), want: "falló para " + redactedValue},
+		{name: "texto sin datos sensibles", field: zap.String("video_id", "abc123"), want: "abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.field(tt.field)
+			if got.Key != tt.field.Key {
+				t.Errorf("key = %q, want %q", got.Key, tt.field.Key)
+			}
+			if got.Type != zapcore.StringType || got.String != tt.want {
+				t.Errorf("valor = %q (tipo %v), want %q", got.String, got.Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorNestedReflect(t *testing.T) {
+	r := newTestRedactor(t)
+
+	input := map[string]interface{}{
+		"headers": map[string]interface{}{
+			"X-Api-Key":    "secreto",
+			"Content-Type": "application/json",
+		},
+		"items": []interface{}{
+			map[string]interface{}{"query": "hola", "note": "Bearer xyz"},
+		},
+		"count": 3,
+	}
+
+	got := r.field(zap.Any("body", input))
+	if got.Type != zapcore.ReflectType {
+		t.Fatalf("tipo = %v, want ReflectType", got.Type)
+	}
+
+	want := map[string]interface{}{
+		"headers": map[string]interface{}{
+			"X-Api-Key":    redactedValue,
+			"Content-Type": "application/json",
+		},
+		"items": []interface{}{
+			map[string]interface{}{"query": Hash("hola"), "note": redactedValue},
+		},
+		"count": float64(3),
+	}
+	if !reflect.DeepEqual(got.Interface, want) {
+		t.Errorf("body = %#v, want %#v", got.Interface, want)
+	}
+
+	// El valor original no se modifica
+	if input["headers"].(map[string]interface{})["X-Api-Key"] != "secreto" {
+		t.Error("se modificó el mapa original")
+	}
+}
+
+func TestRedactingCore(t *testing.T) {
+	r := newTestRedactor(t)
+	observed, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newRedactingCore(observed, r))
+
+	logger.With(zap.String("x-api-key", "secreto"), zap.String("query", "hola")).
+		Info("token Bearer abc123 de ana@example.com", zap.String("authorization", "Bearer abc123"))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("entradas = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+
+	if strings.Contains(entry.Message, "abc123") || strings.Contains(entry.Message, "ana@example.com") {
+		t.Errorf("mensaje sin redactar: %q", entry.Message)
+	}
+
+	fields := entry.ContextMap()
+	want := map[string]interface{}{
+		"x-api-key":     redactedValue,
+		"query":         Hash("hola"),
+		"authorization": redactedValue,
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("campos = %v, want %v", fields, want)
+	}
+}
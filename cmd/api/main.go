@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"io"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
@@ -10,18 +13,37 @@ import (
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/middleware"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/handlers"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
 )
 
 func main() {
-	// Inicializar logger
-	logger := log.Initialize()
-	log.DefaultLogger = logger
+	configPath := flag.String("config", "", "archivo de configuración YAML (por defecto CONFIG_FILE o config.yaml)")
+	checkConfig := flag.Bool("check-config", false, "validar e imprimir la configuración efectiva y salir")
+	flag.Parse()
+
+	if *checkConfig {
+		os.Exit(runCheckConfig(*configPath))
+	}
 
 	// Cargar configuración
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig(*configPath)
+
+	// Inicializar logger (aun si la configuración es inválida, para reportar el error)
+	logger := log.Initialize(log.Options{
+		Level:    cfg.Logging.Level,
+		Encoding: cfg.Logging.Encoding,
+		Outputs:  cfg.Logging.Outputs,
+		Rotation: log.Rotation(cfg.Logging.Rotation),
+		Redactor: newRedactor(cfg.Logging.Redaction),
+	})
+	log.DefaultLogger = logger
+
 	if err != nil {
 		log.Fatal(context.Background(), "Error cargando configuración", log.Err(err))
 	}
+	// El idioma ya fue validado junto con la configuración
+	_ = i18n.SetFallback(cfg.DefaultLanguage)
+	log.Info(context.Background(), "Configuracion cargada correctamente", log.Any("conf", cfg.Redacted()))
 
 	// Inicializar dependencias
 	deps, err := dependencies.NewDependencies(cfg)
@@ -30,7 +52,10 @@ func main() {
 	}
 
 	// Inicializar use cases
-	appUsecases := NewUsecases(deps, cfg)
+	appUsecases, err := NewUsecases(deps, cfg)
+	if err != nil {
+		log.Fatal(context.Background(), "Error inicializando use cases", log.Err(err))
+	}
 
 	// Configurar Gin
 	gin.SetMode(gin.ReleaseMode)
@@ -44,11 +69,14 @@ func main() {
 	r := gin.New()
 
 	// Configurar middlewares personalizados
-	r.Use(middleware.RequestLoggingMiddleware())
+	r.Use(middleware.DebugLogging(cfg.AdminAPIKey))
+	r.Use(middleware.Language())
+	r.Use(middleware.RequestLoggingMiddleware(cfg.Logging.Body))
 	r.Use(middleware.RecoveryWithLogging())
+	r.Use(middleware.ClientIdentity(cfg.APIKeys))
 
 	// Configurar rutas
-	setupRoutes(r, appUsecases)
+	setupRoutes(r, appUsecases, cfg)
 
 	log.Info(context.Background(), "Iniciando servidor en el puerto", log.Any("port", cfg.Port))
 	// Iniciar servidor
@@ -57,27 +85,90 @@ func main() {
 	}
 }
 
+// newRedactor combina las reglas de redacción por defecto con las configuradas
+func newRedactor(extra config.RedactionConfig) *log.Redactor {
+	rules := log.DefaultRedactionRules()
+	rules.MaskKeys = append(rules.MaskKeys, extra.MaskKeys...)
+	rules.HashKeys = append(rules.HashKeys, extra.HashKeys...)
+	rules.Patterns = append(rules.Patterns, extra.Patterns...)
+
+	redactor, err := log.NewRedactor(rules)
+	if err != nil {
+		// Los patrones se validan con la configuración; ante un error se usan solo los por defecto
+		redactor, _ = log.NewRedactor(log.DefaultRedactionRules())
+	}
+	return redactor
+}
+
+// runCheckConfig imprime la configuración efectiva (sin secretos) o los errores de validación
+func runCheckConfig(path string) int {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	dump, err := cfg.Dump()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Print(string(dump))
+	return 0
+}
+
 // setupRoutes configura todas las rutas de la API
-func setupRoutes(r *gin.Engine, usecases Usecases) {
+func setupRoutes(r *gin.Engine, usecases Usecases, cfg config.Config) {
 	// Middleware CORS
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	r.Use(middleware.CORS(cfg.CORS))
+
+	// Rate limiting por clase de ruta
+	defaultLimit, mediaLimit, searchLimit := noLimit, noLimit, noLimit
+	if cfg.RateLimit.Enabled {
+		defaultLimit = middleware.RateLimit("default", cfg.RateLimit.Default)
+		mediaLimit = middleware.RateLimit("media", cfg.RateLimit.Media)
+		searchLimit = middleware.RateLimit("search", cfg.RateLimit.Search)
+	}
+
+	// Cache HTTP por tipo de recurso
+	cache := func(policy config.CachePolicy) gin.HandlerFunc {
+		return middleware.HTTPCache(policy, cfg.HTTPCache.VersionedMaxAgeSeconds)
+	}
 
 	r.GET("/health", handlers.HealthCheck(usecases.HealthUseCase))
-	r.GET("/stats", handlers.GetStats(usecases.StatsUseCase))
-	r.GET("/videos", handlers.GetVideos(usecases.VideoUseCase))
-	r.GET("/video/:id/thumbnail", handlers.ServeThumbnail(usecases.VideoUseCase))
-	r.GET("/video/:id/subtitles", handlers.ServeSubtitles(usecases.VideoUseCase))
-	r.GET("/video/:id/summary", handlers.ServeSummary(usecases.VideoUseCase))
-	r.GET("/video/:id", handlers.ServeVideo(usecases.VideoUseCase))
-	r.POST("/search", handlers.Search(usecases.SearchUseCase))
+	r.GET("/stats", defaultLimit, handlers.GetStats(usecases.StatsUseCase))
+	r.GET("/videos", defaultLimit, handlers.GetVideos(usecases.VideoUseCase))
+	r.GET("/video/:id/thumbnail", mediaLimit, cache(cfg.HTTPCache.Thumbnails), handlers.ServeThumbnail(usecases.ThumbnailUseCase))
+	r.GET("/video/:id/subtitles", mediaLimit, cache(cfg.HTTPCache.Subtitles), handlers.ServeSubtitles(usecases.VideoUseCase))
+	r.GET("/video/:id/subtitles/tracks", defaultLimit, handlers.GetSubtitleTracks(usecases.VideoUseCase))
+	r.GET("/video/:id/transcript", mediaLimit, cache(cfg.HTTPCache.Transcript), handlers.ServeTranscript(usecases.VideoUseCase))
+	r.GET("/video/:id/summary", mediaLimit, cache(cfg.HTTPCache.Summary), handlers.ServeSummary(usecases.SummaryUseCase))
+	r.GET("/video/:id/chapters", mediaLimit, cache(cfg.HTTPCache.Chapters), handlers.ServeChapters(usecases.ChaptersUseCase))
+	if cfg.HLS.Enabled {
+		r.GET("/video/:id/hls/*path", mediaLimit, handlers.ServeHLS(usecases.VideoUseCase,
+			middleware.CacheControl(cfg.HTTPCache.HLSPlaylists), middleware.CacheControl(cfg.HTTPCache.HLSSegments)))
+	}
+	r.GET("/video/:id", mediaLimit, handlers.ServeVideo(usecases.VideoUseCase, middleware.CacheControl(cfg.HTTPCache.Video)))
+	r.POST("/search", searchLimit, handlers.Search(usecases.SearchUseCase))
+
+	// Administración
+	admin := r.Group("/admin", middleware.AdminAuth(cfg.AdminAPIKey))
+	admin.GET("/budget", handlers.GetBudgetStatus(usecases.BudgetUseCase))
+	admin.GET("/prompts", handlers.GetPromptVersions(usecases.PromptUseCase))
+	admin.POST("/prompts/reload", handlers.ReloadPrompts(usecases.PromptUseCase))
+	admin.POST("/videos/:id/summary", handlers.RegenerateSummary(usecases.SummaryUseCase))
+	admin.POST("/videos/:id/chapters", handlers.RegenerateChapters(usecases.ChaptersUseCase))
+	admin.PUT("/videos/:id/chapters", handlers.UpdateChapters(usecases.ChaptersUseCase))
+	admin.POST("/videos/:id/translations", handlers.StartTranslation(usecases.TranslationUseCase))
+	admin.GET("/translations", handlers.ListTranslationJobs(usecases.TranslationUseCase))
+	admin.GET("/translations/:job_id", handlers.GetTranslationJob(usecases.TranslationUseCase))
+
+	r.GET("/usage", middleware.AdminAuth(cfg.AdminAPIKey), handlers.GetUsage(usecases.UsageUseCase))
+
+}
 
+// noLimit es el middleware usado cuando el rate limiting está deshabilitado
+func noLimit(c *gin.Context) {
+	c.Next()
 }
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
+)
+
+// AdminAuth protege los endpoints de administración con el header X-Admin-Key.
+// Si no hay ADMIN_API_KEY configurada, los endpoints quedan deshabilitados.
+func AdminAuth(adminAPIKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if adminAPIKey == "" {
+			AbortWithError(c, http.StatusForbidden, "forbidden", i18n.T(c.Request.Context(), "error.admin_disabled"), "")
+			return
+		}
+
+		key := c.GetHeader("X-Admin-Key")
+		if subtle.ConstantTimeCompare([]byte(key), []byte(adminAPIKey)) != 1 {
+			log.Warn(c.Request.Context(), "Acceso de administración denegado")
+			AbortWithError(c, http.StatusUnauthorized, "unauthorized", i18n.T(c.Request.Context(), "error.admin_unauthorized"), "")
+			return
+		}
+
+		c.Next()
+	}
+}
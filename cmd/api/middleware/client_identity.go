@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+)
+
+// ClientIdentity identifica al cliente por su API key (si es conocida) o por su IP.
+// El identificador queda en el contexto para rate limiting, presupuestos y logs.
+// apiKeys mapea el nombre de cada cliente a su API key.
+func ClientIdentity(apiKeys map[string]string) gin.HandlerFunc {
+	clientsByKey := make(map[string]string, len(apiKeys))
+	for name, key := range apiKeys {
+		clientsByKey[key] = name
+	}
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		clientID := "ip:" + c.ClientIP()
+		if name, ok := clientsByKey[extractAPIKey(c)]; ok {
+			clientID = "key:" + name
+			ctx = reqctx.WithAPIKeyName(ctx, name)
+		}
+
+		ctx = reqctx.WithClientID(ctx, clientID)
+		ctx = log.With(ctx, log.String("client_id", clientID))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// extractAPIKey obtiene la API key desde X-API-Key o Authorization: Bearer
+func extractAPIKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+)
+
+// CORS aplica la política de CORS configurada. Los orígenes admiten "*" (cualquiera),
+// un origen exacto ("https://app.ejemplo.com") o subdominios ("https://*.ejemplo.com").
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	allowMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(cfg.MaxAgeSeconds)
+	anyOrigin := false
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			anyOrigin = true
+		}
+	}
+	// Salvo con "*" sin credenciales la respuesta depende del origen, también cuando
+	// no se envía, y los caches intermedios no deben mezclarlas
+	varyOrigin := !anyOrigin || cfg.AllowCredentials
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if varyOrigin || origin != "" {
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !originAllowed(cfg.AllowedOrigins, origin) {
+			if preflight {
+				log.Warn(c.Request.Context(), "Preflight CORS rechazado", log.String("origin", origin))
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if anyOrigin && !cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+			c.Header("Access-Control-Allow-Methods", allowMethods)
+			c.Header("Access-Control-Allow-Headers", allowHeaders)
+			c.Header("Access-Control-Max-Age", maxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", exposeHeaders)
+		}
+
+		c.Next()
+	}
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+
+		// "https://*.ejemplo.com" acepta "https://app.ejemplo.com" pero no "https://ejemplo.com"
+		scheme, host, ok := strings.Cut(pattern, "://*.")
+		if !ok {
+			continue
+		}
+		prefix := scheme + "://"
+		suffix := "." + host
+		lower := strings.ToLower(origin)
+		if strings.HasPrefix(lower, strings.ToLower(prefix)) &&
+			strings.HasSuffix(lower, strings.ToLower(suffix)) &&
+			len(lower) > len(prefix)+len(suffix) {
+			return true
+		}
+	}
+	return false
+}
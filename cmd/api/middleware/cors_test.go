@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{name: "cualquiera", allowed: []string{"*"}, origin: "https://evil.net", want: true},
+		{name: "exacto", allowed: []string{"https://app.example.com"}, origin: "https://app.example.com", want: true},
+		{name: "exacto sin distinguir mayúsculas", allowed: []string{"https://app.example.com"}, origin: "https://APP.example.com", want: true},
+		{name: "exacto con otro esquema", allowed: []string{"https://app.example.com"}, origin: "http://app.example.com", want: false},
+		{name: "subdominio", allowed: []string{"https://*.example.com"}, origin: "https://app.example.com", want: true},
+		{name: "subdominio anidado", allowed: []string{"https://*.example.com"}, origin: "https://a.b.example.com", want: true},
+		{name: "subdominio en mayúsculas", allowed: []string{"https://*.example.com"}, origin: "https://App.Example.com", want: true},
+		{name: "dominio base", allowed: []string{"https://*.example.com"}, origin: "https://example.com", want: false},
+		{name: "sufijo sin punto", allowed: []string{"https://*.example.com"}, origin: "https://evil-example.com", want: false},
+		{name: "dominio como prefijo", allowed: []string{"https://*.example.com"}, origin: "https://example.com.evil.net", want: false},
+		{name: "subdominio como prefijo", allowed: []string{"https://*.example.com"}, origin: "https://app.example.com.evil.net", want: false},
+		{name: "subdominio vacío", allowed: []string{"https://*.example.com"}, origin: "https://.example.com", want: false},
+		{name: "subdominio con otro esquema", allowed: []string{"https://*.example.com"}, origin: "http://app.example.com", want: false},
+		{name: "sin orígenes", allowed: nil, origin: "https://app.example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := originAllowed(tt.allowed, tt.origin); got != tt.want {
+				t.Errorf("originAllowed(%v, %q) = %v, want %v", tt.allowed, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCORSVaryOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		cfg      config.CORSConfig
+		origin   string
+		wantVary bool
+		wantACAO string
+	}{
+		{name: "lista sin Origin", cfg: config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}, wantVary: true},
+		{name: "lista con Origin", cfg: config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}, origin: "https://app.example.com", wantVary: true, wantACAO: "https://app.example.com"},
+		{name: "lista con Origin rechazado", cfg: config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}, origin: "https://evil.net", wantVary: true},
+		{name: "* con credenciales sin Origin", cfg: config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, wantVary: true},
+		{name: "* con credenciales con Origin", cfg: config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, origin: "https://evil.net", wantVary: true, wantACAO: "https://evil.net"},
+		{name: "* sin Origin", cfg: config.CORSConfig{AllowedOrigins: []string{"*"}}, wantVary: false},
+		{name: "* con Origin", cfg: config.CORSConfig{AllowedOrigins: []string{"*"}}, origin: "https://evil.net", wantVary: true, wantACAO: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(CORS(tt.cfg))
+			r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			vary := false
+			for _, v := range w.Header().Values("Vary") {
+				if v == "Origin" {
+					vary = true
+				}
+			}
+			if vary != tt.wantVary {
+				t.Errorf("Vary: Origin = %v, want %v", vary, tt.wantVary)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantACAO {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantACAO)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+)
+
+// debugTokenTTL es la validez máxima de un header de debug firmado
+const debugTokenTTL = 5 * time.Minute
+
+// DebugLogging habilita logs de nivel debug solo para la solicitud que envía el header
+// X-Debug-Log con el formato "<unix_timestamp>.<hex(HMAC-SHA256(admin_key, "<unix_timestamp>|<METHOD>|<path>"))>",
+// con la firma en hex minúscula. La firma ata el token a un método y una ruta, y cada token se acepta una sola vez.
+// Debe registrarse antes de RequestLoggingMiddleware para que aplique a todos los logs del request.
+func DebugLogging(adminAPIKey string) gin.HandlerFunc {
+	used := &debugTokens{seen: make(map[string]time.Time)}
+
+	return func(c *gin.Context) {
+		token := c.GetHeader("X-Debug-Log")
+		if token == "" || adminAPIKey == "" {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		if !validDebugToken(token, adminAPIKey, c.Request.Method, c.Request.URL.Path, now) {
+			log.Warn(c.Request.Context(), "Header X-Debug-Log inválido o vencido")
+			c.Next()
+			return
+		}
+		if !used.claim(token, now) {
+			log.Warn(c.Request.Context(), "Header X-Debug-Log ya utilizado")
+			c.Next()
+			return
+		}
+
+		ctx := log.EnableDebug(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		log.Debug(ctx, "Logs de debug habilitados para la solicitud")
+
+		c.Next()
+	}
+}
+
+// debugTokens recuerda los tokens aceptados mientras siguen vigentes, para rechazar
+// su reutilización
+type debugTokens struct {
+	mu   sync.Mutex
+	seen map[string]time.Time // token -> vencimiento
+}
+
+// claim marca el token como usado. Retorna false si ya se había usado.
+func (d *debugTokens) claim(token string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for seen, expires := range d.seen {
+		if now.After(expires) {
+			delete(d.seen, seen)
+		}
+	}
+	if _, ok := d.seen[token]; ok {
+		return false
+	}
+	// El timestamp puede estar hasta debugTokenTTL en el futuro
+	d.seen[token] = now.Add(2 * debugTokenTTL)
+	return true
+}
+
+func validDebugToken(token, key, method, path string, now time.Time) bool {
+	timestamp, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > debugTokenTTL || age < -debugTokenTTL {
+		return false
+	}
+
+	// Solo se acepta la forma canónica: con otra capitalización el mismo token
+	// pasaría como nuevo y se podría reutilizar
+	if signature != strings.ToLower(signature) {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + "|" + method + "|" + path))
+	return hmac.Equal(got, mac.Sum(nil))
+}
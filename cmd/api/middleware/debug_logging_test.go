@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testAdminKey = "admin-secreta"
+
+func signDebugToken(key, method, path string, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + "|" + method + "|" + path))
+	return timestamp + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidDebugToken(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	token := signDebugToken(testAdminKey, "GET", "/stats", now)
+	timestamp, signature, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name   string
+		token  string
+		key    string
+		method string
+		path   string
+		now    time.Time
+		want   bool
+	}{
+		{name: "válido", token: token, key: testAdminKey, method: "GET", path: "/stats", now: now, want: true},
+		{name: "dentro del TTL", token: token, key: testAdminKey, method: "GET", path: "/stats", now: now.Add(debugTokenTTL), want: true},
+		{name: "vencido", token: token, key: testAdminKey, method: "GET", path: "/stats", now: now.Add(debugTokenTTL + time.Second), want: false},
+		{name: "futuro", token: token, key: testAdminKey, method: "GET", path: "/stats", now: now.Add(-debugTokenTTL - time.Second), want: false},
+		{name: "otro método", token: token, key: testAdminKey, method: "POST", path: "/stats", now: now, want: false},
+		{name: "otra ruta", token: token, key: testAdminKey, method: "GET", path: "/usage", now: now, want: false},
+		{name: "otra clave", token: token, key: "otra", method: "GET", path: "/stats", now: now, want: false},
+		{name: "firma en mayúsculas", token: timestamp + "." + strings.ToUpper(signature), key: testAdminKey, method: "GET", path: "/stats", now: now, want: false},
+		{name: "sin separador", token: timestamp + signature, key: testAdminKey, method: "GET", path: "/stats", now: now, want: false},
+		{name: "timestamp inválido", token: "abc." + signature, key: testAdminKey, method: "GET", path: "/stats", now: now, want: false},
+		{name: "firma no hex", token: timestamp + ".zz", key: testAdminKey, method: "GET", path: "/stats", now: now, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validDebugToken(tt.token, tt.key, tt.method, tt.path, tt.now); got != tt.want {
+				t.Errorf("validDebugToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugTokensClaim(t *testing.T) {
+	used := &debugTokens{seen: make(map[string]time.Time)}
+	now := time.Unix(1_700_000_000, 0)
+
+	if !used.claim("a", now) {
+		t.Fatal("primer uso rechazado")
+	}
+	if used.claim("a", now.Add(debugTokenTTL)) {
+		t.Error("reutilización aceptada")
+	}
+	if !used.claim("b", now) {
+		t.Error("otro token rechazado")
+	}
+
+	// Pasado el vencimiento el token se olvida (y ya no pasaría la validación)
+	used.claim("c", now.Add(2*debugTokenTTL+time.Second))
+	if _, ok := used.seen["a"]; ok {
+		t.Error("el token vencido sigue registrado")
+	}
+}
+
+func TestDebugLoggingReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var enabled bool
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		original := c.Request.Context()
+		c.Next()
+		enabled = c.Request.Context() != original
+	})
+	r.Use(DebugLogging(testAdminKey))
+	r.GET("/stats", func(c *gin.Context) {})
+
+	send := func(token string) bool {
+		req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+		req.Header.Set("X-Debug-Log", token)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		return enabled
+	}
+
+	token := signDebugToken(testAdminKey, "GET", "/stats", time.Now())
+	timestamp, signature, _ := strings.Cut(token, ".")
+
+	if !send(token) {
+		t.Fatal("el token válido no habilitó debug")
+	}
+	if send(token) {
+		t.Error("el token reutilizado habilitó debug")
+	}
+	if send(timestamp + "." + strings.ToUpper(signature)) {
+		t.Error("el token reutilizado en mayúsculas habilitó debug")
+	}
+	if send(signDebugToken(testAdminKey, "GET", "/usage", time.Now())) {
+		t.Error("un token de otra ruta habilitó debug")
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+)
+
+// AbortWithError responde un error con el formato común de la API
+func AbortWithError(c *gin.Context, status int, code, message, details string) {
+	c.AbortWithStatusJSON(status, models.ErrorResponse{
+		Error:     message,
+		Code:      code,
+		RequestID: reqctx.RequestID(c.Request.Context()),
+		Details:   details,
+	})
+}
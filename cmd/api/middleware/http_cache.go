@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/contenthash"
+)
+
+// bufferedWriter retiene la respuesta para calcular el ETag antes de enviarla
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Flush() {}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// HTTPCache agrega a las respuestas exitosas un ETag fuerte con el hash del contenido y
+// el Cache-Control de la política. Si el cliente ya tiene esa versión (If-None-Match, o
+// If-Modified-Since cuando el handler indica Last-Modified) responde 304 sin cuerpo.
+// Con ?v= igual al hash la respuesta se cachea como immutable.
+func HTTPCache(policy config.CachePolicy, versionedMaxAge int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		w := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = original
+
+		header := original.Header()
+		if w.status != http.StatusOK {
+			// Errores, 206 y 304 generados por el handler (http.ServeContent evalúa
+			// If-Modified-Since) se envían sin cambios
+			if w.status == http.StatusNotModified {
+				header.Set("Cache-Control", cacheControl(policy, versionedMaxAge, false))
+			}
+			original.WriteHeader(w.status)
+			original.Write(w.body.Bytes())
+			return
+		}
+
+		hash := contenthash.Bytes(w.body.Bytes())
+		etag := `"` + hash + `"`
+		header.Set("ETag", etag)
+		header.Set("Cache-Control", cacheControl(policy, versionedMaxAge, c.Query("v") == hash))
+		header.Del("Pragma")
+		header.Del("Expires")
+		// El formato de la respuesta se negocia con Accept (el idioma ya está en Vary)
+		header.Add("Vary", "Accept")
+
+		if notModified(c.Request, etag, header.Get("Last-Modified")) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		original.WriteHeader(http.StatusOK)
+		original.Write(w.body.Bytes())
+	}
+}
+
+// CacheControl retorna el Cache-Control de la política, para los archivos grandes que
+// se sirven sin pasar por HTTPCache (video y HLS)
+func CacheControl(policy config.CachePolicy) string {
+	return cacheControl(policy, 0, false)
+}
+
+// cacheControl arma el header Cache-Control de la política
+func cacheControl(policy config.CachePolicy, versionedMaxAge int, versioned bool) string {
+	if policy.NoStore {
+		return "no-store"
+	}
+
+	scope := "public"
+	if policy.Private {
+		scope = "private"
+	}
+	switch {
+	case versioned && versionedMaxAge > 0:
+		return fmt.Sprintf("%s, max-age=%d, immutable", scope, versionedMaxAge)
+	case policy.MaxAgeSeconds == 0:
+		return scope + ", no-cache"
+	default:
+		return fmt.Sprintf("%s, max-age=%d", scope, policy.MaxAgeSeconds)
+	}
+}
+
+// notModified evalúa los headers condicionales. If-None-Match tiene prioridad sobre
+// If-Modified-Since (RFC 9110).
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since := r.Header.Get("If-Modified-Since")
+	if since == "" || lastModified == "" {
+		return false
+	}
+	sinceTime, err := http.ParseTime(since)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(sinceTime)
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
+)
+
+// Language negocia el idioma de los mensajes de la respuesta. El parámetro ?lang=
+// tiene prioridad sobre Accept-Language; si ninguno es soportado se usa el idioma por defecto.
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"))
+
+		c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), lang))
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+)
+
+// sweepInterval cada cuánto se eliminan los buckets inactivos
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter implementa un token bucket por cliente para una clase de rutas
+type rateLimiter struct {
+	mu        sync.Mutex
+	rule      config.RateLimitRule
+	perSecond float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type rateLimitResult struct {
+	allowed    bool
+	remaining  int
+	reset      int // segundos hasta que el bucket vuelve a estar lleno
+	retryAfter int // segundos hasta el próximo token disponible
+}
+
+func newRateLimiter(rule config.RateLimitRule) *rateLimiter {
+	return &rateLimiter{
+		rule:      rule,
+		perSecond: rule.RequestsPerMinute / 60.0,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *rateLimiter) take(key string, now time.Time) rateLimitResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	burst := float64(l.rule.Burst)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*l.perSecond)
+	b.last = now
+
+	result := rateLimitResult{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.allowed = true
+	} else {
+		result.retryAfter = int(math.Ceil((1 - b.tokens) / l.perSecond))
+	}
+
+	result.remaining = int(math.Floor(b.tokens))
+	result.reset = int(math.Ceil((burst - b.tokens) / l.perSecond))
+
+	return result
+}
+
+// sweep elimina los buckets que ya se recargaron por completo
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	fullAfter := time.Duration(float64(l.rule.Burst) / l.perSecond * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > fullAfter {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimit limita las solicitudes por cliente para una clase de rutas ("search", "media", ...).
+// Agrega los headers RateLimit-* y responde 429 con Retry-After cuando se agota el bucket.
+func RateLimit(class string, rule config.RateLimitRule) gin.HandlerFunc {
+	limiter := newRateLimiter(rule)
+	policy := fmt.Sprintf("%g;w=60;burst=%d", rule.RequestsPerMinute, rule.Burst)
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		clientID := reqctx.ClientID(ctx)
+		if clientID == "" {
+			clientID = "ip:" + c.ClientIP()
+		}
+
+		result := limiter.take(clientID, time.Now())
+
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(rule.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(result.reset))
+
+		if !result.allowed {
+			log.Warn(ctx, "Rate limit excedido",
+				log.String("class", class),
+				log.Int("retry_after", result.retryAfter),
+			)
+
+			c.Header("Retry-After", strconv.Itoa(result.retryAfter))
+			AbortWithError(c, http.StatusTooManyRequests, "rate_limited",
+				i18n.T(ctx, "error.rate_limited"),
+				i18n.T(ctx, "rate_limit.retry_after", result.retryAfter))
+			return
+		}
+
+		c.Next()
+	}
+}
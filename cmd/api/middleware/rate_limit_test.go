@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+)
+
+func TestRateLimiterTake(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rule := config.RateLimitRule{RequestsPerMinute: 30, Burst: 3} // un token cada 2 segundos
+
+	type step struct {
+		at         time.Duration // desde start
+		allowed    bool
+		remaining  int
+		reset      int
+		retryAfter int
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "consume el burst y rechaza",
+			steps: []step{
+				{at: 0, allowed: true, remaining: 2, reset: 2},
+				{at: 0, allowed: true, remaining: 1, reset: 4},
+				{at: 0, allowed: true, remaining: 0, reset: 6},
+				{at: 0, allowed: false, remaining: 0, reset: 6, retryAfter: 2},
+			},
+		},
+		{
+			name: "retry-after con recarga parcial",
+			steps: []step{
+				{at: 0, allowed: true, remaining: 2, reset: 2},
+				{at: 0, allowed: true, remaining: 1, reset: 4},
+				{at: 0, allowed: true, remaining: 0, reset: 6},
+				{at: 1500 * time.Millisecond, allowed: false, remaining: 0, reset: 5, retryAfter: 1},
+				{at: 2 * time.Second, allowed: true, remaining: 0, reset: 6},
+			},
+		},
+		{
+			name: "la recarga no supera el burst",
+			steps: []step{
+				{at: 0, allowed: true, remaining: 2, reset: 2},
+				{at: time.Hour, allowed: true, remaining: 2, reset: 2},
+				{at: time.Hour, allowed: true, remaining: 1, reset: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newRateLimiter(rule)
+			for i, s := range tt.steps {
+				got := limiter.take("key:test", start.Add(s.at))
+				want := rateLimitResult{allowed: s.allowed, remaining: s.remaining, reset: s.reset, retryAfter: s.retryAfter}
+				if got != want {
+					t.Fatalf("paso %d: take() = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterKeysAreIndependent(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(config.RateLimitRule{RequestsPerMinute: 60, Burst: 1})
+
+	if !limiter.take("key:a", now).allowed {
+		t.Fatal("primera solicitud de key:a rechazada")
+	}
+	if limiter.take("key:a", now).allowed {
+		t.Fatal("segunda solicitud de key:a aceptada")
+	}
+	if !limiter.take("ip:10.0.0.1", now).allowed {
+		t.Fatal("primera solicitud de otro cliente rechazada")
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(config.RateLimitRule{RequestsPerMinute: 60, Burst: 2})
+	limiter.lastSweep = now
+
+	limiter.take("key:a", now)
+	limiter.take("key:b", now.Add(time.Minute))
+	limiter.take("key:c", now.Add(2*time.Minute))
+
+	// key:a se llena en 2 segundos: el sweep del minuto siguiente la elimina
+	if _, ok := limiter.buckets["key:a"]; ok {
+		t.Error("el bucket de key:a no se eliminó")
+	}
+	if _, ok := limiter.buckets["key:c"]; !ok {
+		t.Error("el bucket de key:c se eliminó")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rule := config.RateLimitRule{RequestsPerMinute: 6, Burst: 1}
+
+	tests := []struct {
+		name       string
+		clientIDs  []string // reqctx.ClientID de cada solicitud; vacío usa la IP
+		wantStatus []int
+	}{
+		{name: "misma API key", clientIDs: []string{"key:ana", "key:ana"}, wantStatus: []int{http.StatusOK, http.StatusTooManyRequests}},
+		{name: "API keys distintas", clientIDs: []string{"key:ana", "key:beto"}, wantStatus: []int{http.StatusOK, http.StatusOK}},
+		{name: "sin identidad usa la IP", clientIDs: []string{"", ""}, wantStatus: []int{http.StatusOK, http.StatusTooManyRequests}},
+		{name: "la API key no comparte bucket con la IP", clientIDs: []string{"", "key:ana"}, wantStatus: []int{http.StatusOK, http.StatusOK}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(RateLimit("search", rule))
+			r.GET("/buscar", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			for i, clientID := range tt.clientIDs {
+				req := httptest.NewRequest(http.MethodGet, "/buscar", nil)
+				req.RemoteAddr = "10.0.0.1:1234"
+				if clientID != "" {
+					req = req.WithContext(reqctx.WithClientID(req.Context(), clientID))
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				if w.Code != tt.wantStatus[i] {
+					t.Fatalf("solicitud %d: status = %d, want %d", i, w.Code, tt.wantStatus[i])
+				}
+				if got := w.Header().Get("RateLimit-Policy"); got != "6;w=60;burst=1" {
+					t.Errorf("RateLimit-Policy = %q", got)
+				}
+				if got := w.Header().Get("RateLimit-Limit"); got != "1" {
+					t.Errorf("RateLimit-Limit = %q", got)
+				}
+				if got := w.Header().Get("RateLimit-Remaining"); got != "0" {
+					t.Errorf("RateLimit-Remaining = %q", got)
+				}
+				retryAfter := w.Header().Get("Retry-After")
+				if w.Code == http.StatusTooManyRequests && retryAfter != "10" {
+					t.Errorf("Retry-After = %q, want 10", retryAfter)
+				}
+				if w.Code == http.StatusOK && retryAfter != "" {
+					t.Errorf("Retry-After en respuesta aceptada: %q", retryAfter)
+				}
+			}
+		})
+	}
+}
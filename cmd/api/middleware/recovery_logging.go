@@ -12,6 +12,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
 )
 
 // RecoveryWithLogging middleware que captura panics y los loggea apropiadamente
@@ -32,7 +34,7 @@ func RecoveryWithLogging() gin.HandlerFunc {
 
 				// Obtener contexto con request ID si está disponible
 				ctx := c.Request.Context()
-				requestID := GetRequestID(ctx)
+				requestID := reqctx.RequestID(ctx)
 
 				// Preparar campos de log
 				fields := []log.Field{
@@ -62,7 +64,7 @@ func RecoveryWithLogging() gin.HandlerFunc {
 				}
 
 				// Responder con error 500
-				c.AbortWithStatus(http.StatusInternalServerError)
+				AbortWithError(c, http.StatusInternalServerError, "internal_error", i18n.T(c.Request.Context(), "error.internal"), "")
 			}
 		}()
 		c.Next()
@@ -88,7 +90,7 @@ func LoggingWithMetrics() gin.HandlerFunc {
 
 		// Obtener contexto
 		ctx := c.Request.Context()
-		requestID := GetRequestID(ctx)
+		requestID := reqctx.RequestID(ctx)
 
 		// Preparar campos de log
 		fields := []log.Field{
@@ -2,34 +2,52 @@ package middleware
 
 import (
 	"bytes"
-	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
 )
 
-type RequestIDKey struct{}
-
+// responseWriterWrapper copia el cuerpo de la respuesta para loggearlo. Guarda a lo
+// sumo limit bytes: uno más que max_bytes, para saber si hubo que truncar.
 type responseWriterWrapper struct {
 	gin.ResponseWriter
-	body *bytes.Buffer
+	body  *bytes.Buffer
+	limit int
 }
 
-func (w responseWriterWrapper) Write(b []byte) (int, error) {
-	w.body.Write(b)
+func (w *responseWriterWrapper) Write(b []byte) (int, error) {
+	w.capture(b)
 	return w.ResponseWriter.Write(b)
 }
 
+func (w *responseWriterWrapper) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *responseWriterWrapper) capture(b []byte) {
+	if room := w.limit - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
+}
+
 func generateRequestID() string {
 	bytes := make([]byte, 8)
 	rand.Read(bytes)
 	return hex.EncodeToString(bytes)
 }
 
-func RequestLoggingMiddleware() gin.HandlerFunc {
+// RequestLoggingMiddleware loggea cada solicitud. El cuerpo de la respuesta se loggea
+// según la regla de la ruta (o la regla por defecto), con muestreo y límite de bytes.
+func RequestLoggingMiddleware(bodyConfig config.BodyLoggingConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := generateRequestID()
 
@@ -44,29 +62,37 @@ func RequestLoggingMiddleware() gin.HandlerFunc {
 			log.String("user_agent", c.Request.UserAgent()),
 		)
 
-		ctx = context.WithValue(ctx, RequestIDKey{}, requestID)
+		ctx = reqctx.WithRequestID(ctx, requestID)
+		ctx = reqctx.WithRoute(ctx, c.FullPath())
 
 		c.Request = c.Request.WithContext(ctx)
 
 		c.Header("X-Request-ID", requestID)
 
-		blw := &responseWriterWrapper{
-			ResponseWriter: c.Writer,
-			body:           bytes.NewBufferString(""),
+		rule, ok := bodyConfig.Routes[c.FullPath()]
+		if !ok {
+			rule = bodyConfig.Default
+		}
+
+		var blw *responseWriterWrapper
+		if rule.Enabled && mathrand.Float64() < rule.SampleRate {
+			blw = &responseWriterWrapper{
+				ResponseWriter: c.Writer,
+				body:           bytes.NewBufferString(""),
+				limit:          rule.MaxBytes + 1,
+			}
+			c.Writer = blw
 		}
-		c.Writer = blw
 
 		c.Next()
 
 		status := c.Writer.Status()
-		responseBody := blw.body.String()
-		if len(responseBody) > 1000 {
-			responseBody = responseBody[:1000] + "..."
-		}
 		fields := []log.Field{
 			log.Int("status", status),
 			log.Duration("duration", time.Since(start)),
-			log.String("response_body", responseBody),
+		}
+		if blw != nil {
+			fields = append(fields, responseBodyField(blw.body.Bytes(), rule.MaxBytes, c.Writer.Size()))
 		}
 
 		switch {
@@ -80,9 +106,17 @@ func RequestLoggingMiddleware() gin.HandlerFunc {
 	}
 }
 
-func GetRequestID(ctx context.Context) string {
-	if requestID, ok := ctx.Value(RequestIDKey{}).(string); ok {
-		return requestID
+// responseBodyField loggea el cuerpo como JSON, para que el redactor aplique las reglas
+// por key, o como texto si no es JSON (solo se aplican los patrones). Un cuerpo de más
+// de maxBytes no se loggea: truncado no se puede parsear y las reglas por key (por
+// ejemplo la de "query") no se aplicarían; se loggea solo su tamaño.
+func responseBodyField(body []byte, maxBytes, size int) log.Field {
+	if len(body) > maxBytes {
+		return log.String("response_body", fmt.Sprintf("[%d bytes, no se loggea]", size))
+	}
+	var value interface{}
+	if json.Unmarshal(body, &value) == nil {
+		return log.Any("response_body", value)
 	}
-	return ""
+	return log.String("response_body", string(body))
 }
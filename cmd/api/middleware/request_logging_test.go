@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+)
+
+func TestResponseBodyField(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		maxBytes int
+		size     int
+		wantJSON bool
+		want     string
+	}{
+		{name: "JSON se parsea", body: `{"ok":true}`, maxBytes: 100, size: 11, wantJSON: true},
+		{name: "texto tal cual", body: "hola", maxBytes: 100, size: 4, want: "hola"},
+		{name: "justo en el límite", body: "hola", maxBytes: 4, size: 4, want: "hola"},
+		{name: "truncado no se loggea", body: `{"query":"`, maxBytes: 9, size: 5000, want: "[5000 bytes, no se loggea]"},
+		{name: "vacío", body: "", maxBytes: 100, size: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := responseBodyField([]byte(tt.body), tt.maxBytes, tt.size)
+			if field.Key != "response_body" {
+				t.Fatalf("key = %q", field.Key)
+			}
+			if tt.wantJSON {
+				if field.Interface == nil {
+					t.Errorf("response_body = %q, want JSON parseado", field.String)
+				}
+				return
+			}
+			if field.String != tt.want {
+				t.Errorf("response_body = %q, want %q", field.String, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseWriterWrapperLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := strings.Repeat("x", 5000)
+
+	var captured int
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		if w, ok := c.Writer.(*responseWriterWrapper); ok {
+			captured = w.body.Len()
+		}
+	})
+	r.Use(RequestLoggingMiddleware(config.BodyLoggingConfig{
+		Default: config.BodyLoggingRule{Enabled: true, SampleRate: 1, MaxBytes: 100},
+	}))
+	r.GET("/texto", func(c *gin.Context) {
+		for i := 0; i < 5; i++ {
+			c.Writer.WriteString(body[:1000])
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/texto", nil))
+
+	if w.Body.Len() != len(body) {
+		t.Errorf("el cliente recibió %d bytes, want %d", w.Body.Len(), len(body))
+	}
+	if captured != 101 {
+		t.Errorf("se copiaron %d bytes, want 101", captured)
+	}
+}
@@ -8,18 +8,39 @@ import (
 
 // Usecases contiene todos los use cases de la aplicación
 type Usecases struct {
-	SearchUseCase usecases.SearchUseCase
-	HealthUseCase usecases.HealthUseCase
-	StatsUseCase  usecases.StatsUseCase
-	VideoUseCase  usecases.VideoUseCase
+	SearchUseCase      usecases.SearchUseCase
+	PromptUseCase      usecases.PromptUseCase
+	BudgetUseCase      usecases.BudgetUseCase
+	UsageUseCase       usecases.UsageUseCase
+	HealthUseCase      usecases.HealthUseCase
+	StatsUseCase       usecases.StatsUseCase
+	VideoUseCase       usecases.VideoUseCase
+	ThumbnailUseCase   usecases.ThumbnailUseCase
+	SummaryUseCase     usecases.SummaryUseCase
+	ChaptersUseCase    usecases.ChaptersUseCase
+	TranslationUseCase usecases.TranslationUseCase
 }
 
 // NewUsecases crea una nueva instancia de use cases
-func NewUsecases(deps dependencies.Dependencies, cfg config.Config) Usecases {
-	return Usecases{
-		SearchUseCase: usecases.NewSearchUseCase(deps.OpenAIService, deps.PineconeService, cfg),
-		HealthUseCase: usecases.NewHealthUseCase(deps.PineconeService),
-		StatsUseCase:  usecases.NewStatsUseCase(deps.PineconeService),
-		VideoUseCase:  usecases.NewVideoUseCase(cfg),
+func NewUsecases(deps dependencies.Dependencies, cfg config.Config) (Usecases, error) {
+	budgetUseCase, err := usecases.NewBudgetUseCase(cfg, deps.UsageLedger)
+	if err != nil {
+		return Usecases{}, err
 	}
+	usageUseCase := usecases.NewUsageUseCase(deps.UsageLedger)
+	promptUseCase := usecases.NewPromptUseCase(deps.PromptStore, cfg.Prompts)
+
+	return Usecases{
+		SearchUseCase:      usecases.NewSearchUseCase(deps.OpenAIService, deps.PineconeService, deps.ChatModel, promptUseCase, budgetUseCase, usageUseCase, cfg),
+		PromptUseCase:      promptUseCase,
+		BudgetUseCase:      budgetUseCase,
+		UsageUseCase:       usageUseCase,
+		HealthUseCase:      usecases.NewHealthUseCase(deps.PineconeService),
+		StatsUseCase:       usecases.NewStatsUseCase(deps.PineconeService),
+		VideoUseCase:       usecases.NewVideoUseCase(cfg),
+		ThumbnailUseCase:   usecases.NewThumbnailUseCase(cfg),
+		SummaryUseCase:     usecases.NewSummaryUseCase(deps.ChatModel, budgetUseCase, usageUseCase, cfg),
+		ChaptersUseCase:    usecases.NewChaptersUseCase(deps.OpenAIService, deps.ChatModel, budgetUseCase, usageUseCase, cfg),
+		TranslationUseCase: usecases.NewTranslationUseCase(deps.ChatModel, budgetUseCase, usageUseCase, cfg),
+	}, nil
 }
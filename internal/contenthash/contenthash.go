@@ -0,0 +1,50 @@
+// Package contenthash calcula el hash de contenido que usan los ETag y las URL
+// versionadas, compartido por los middlewares y los use cases.
+package contenthash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sync"
+	"time"
+)
+
+// Bytes retorna el hash del contenido usado en los ETag y en las URL versionadas
+func Bytes(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:16])
+}
+
+// fileHash es el hash de un archivo en una fecha de modificación y tamaño
+type fileHash struct {
+	modTime time.Time
+	size    int64
+	hash    string
+}
+
+// fileHashes guarda el hash de cada archivo para no releerlo mientras no cambie
+var fileHashes sync.Map // ruta -> fileHash
+
+// File retorna el hash del contenido de un archivo. Solo lo relee si cambió su fecha
+// de modificación o su tamaño.
+func File(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if value, ok := fileHashes.Load(path); ok {
+		cached := value.(fileHash)
+		if cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+			return cached.hash, nil
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	hash := Bytes(content)
+	fileHashes.Store(path, fileHash{modTime: info.ModTime(), size: info.Size(), hash: hash})
+	return hash, nil
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+func GetBudgetStatus(budgetUseCase usecases.BudgetUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("budget_status"))
+
+		status, err := budgetUseCase.GetStatus(ctx)
+		if err != nil {
+			respondError(ctx, c, "error.budget", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
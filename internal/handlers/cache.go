@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setLastModified indica la fecha de modificación del recurso para If-Modified-Since.
+// Una fecha vacía no agrega el header.
+func setLastModified(c *gin.Context, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	c.Header("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
+
+// fileETag arma un ETag con el tamaño y la fecha de modificación del archivo, para los
+// archivos grandes que no conviene leer para calcular su hash
+func fileETag(info os.FileInfo) string {
+	return fmt.Sprintf(`"%x-%x"`, info.Size(), info.ModTime().UnixNano())
+}
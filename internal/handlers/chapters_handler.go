@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/subtitles"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+// ServeChapters sirve los capítulos del video como JSON (por defecto) o como pista
+// WebVTT de capítulos (kind="chapters")
+func ServeChapters(chaptersUseCase usecases.ChaptersUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("get_chapters"))
+
+		format, err := negotiateFormat(c, "json", "vtt")
+		if err != nil {
+			respondError(ctx, c, "error.invalid_request", err)
+			return
+		}
+
+		chapters, err := chaptersUseCase.GetChapters(ctx, c.Param("id"))
+		if err != nil {
+			respondError(ctx, c, "error.chapters", err)
+			return
+		}
+
+		switch {
+		case chapters.UpdatedAt != nil:
+			setLastModified(c, *chapters.UpdatedAt)
+		case chapters.GeneratedAt != nil:
+			setLastModified(c, *chapters.GeneratedAt)
+		}
+
+		if format == "json" {
+			c.JSON(http.StatusOK, chapters)
+			return
+		}
+
+		cues := make([]subtitles.Cue, len(chapters.Chapters))
+		for i, chapter := range chapters.Chapters {
+			cues[i] = subtitles.Cue{
+				ID:    strconv.Itoa(i + 1),
+				Start: subtitles.FromSeconds(chapter.StartSec),
+				End:   subtitles.FromSeconds(chapter.EndSec),
+				Text:  chapter.Title,
+			}
+		}
+
+		c.Status(http.StatusOK)
+		c.Header("Content-Type", "text/vtt; charset=utf-8")
+		if err := subtitles.WriteVTT(c.Writer, cues); err != nil {
+			log.Error(ctx, "Error escribiendo capítulos", log.Err(err))
+		}
+	}
+}
+
+// RegenerateChapters detecta los capítulos del video desde la transcripción y reemplaza los guardados
+func RegenerateChapters(chaptersUseCase usecases.ChaptersUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("regenerate_chapters"))
+
+		chapters, err := chaptersUseCase.Regenerate(ctx, c.Param("id"))
+		if err != nil {
+			respondError(ctx, c, "error.chapters", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, chapters)
+	}
+}
+
+// UpdateChapters reemplaza los capítulos del video por los del cuerpo de la solicitud
+func UpdateChapters(chaptersUseCase usecases.ChaptersUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("update_chapters"))
+
+		var req models.ChaptersUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.chapters_body"))
+			return
+		}
+
+		chapters, err := chaptersUseCase.Update(ctx, c.Param("id"), req)
+		if err != nil {
+			respondError(ctx, c, "error.chapters", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, chapters)
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+// statusByKind mapea cada clasificación de error a su status HTTP
+var statusByKind = map[usecases.ErrorKind]int{
+	usecases.KindValidation:          http.StatusBadRequest,
+	usecases.KindNotFound:            http.StatusNotFound,
+	usecases.KindUpstreamUnavailable: http.StatusServiceUnavailable,
+	usecases.KindQuotaExceeded:       http.StatusServiceUnavailable,
+	usecases.KindBudgetExceeded:      http.StatusTooManyRequests,
+	usecases.KindInternal:            http.StatusInternalServerError,
+}
+
+// respondError responde un error de use case con el status y el código que le corresponden.
+// messageKey es la key del catálogo para el mensaje principal. Los detalles de errores
+// internos y de servicios externos solo se loggean.
+func respondError(ctx context.Context, c *gin.Context, messageKey string, err error) {
+	kind := usecases.KindOf(err)
+	message := i18n.T(ctx, messageKey)
+
+	status, ok := statusByKind[kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	response := models.ErrorResponse{
+		Error:     message,
+		Code:      string(kind),
+		RequestID: reqctx.RequestID(ctx),
+	}
+
+	switch kind {
+	case usecases.KindValidation, usecases.KindNotFound, usecases.KindBudgetExceeded:
+		response.Details = err.Error()
+		var domainErr *usecases.Error
+		if errors.As(err, &domainErr) {
+			response.Details = domainErr.Localized(ctx)
+		}
+		log.Warn(ctx, i18n.Translate(i18n.Spanish, messageKey), log.Err(err), log.String("code", response.Code))
+	default:
+		log.Error(ctx, i18n.Translate(i18n.Spanish, messageKey), log.Err(err), log.String("code", response.Code))
+	}
+
+	c.AbortWithStatusJSON(status, response)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+// formatMIME asocia cada formato de respuesta con su content type
+var formatMIME = map[string]string{
+	"json":     "application/json",
+	"markdown": "text/markdown",
+	"text":     "text/plain",
+	"vtt":      "text/vtt",
+	"srt":      "application/x-subrip",
+	"jpeg":     "image/jpeg",
+	"png":      "image/png",
+}
+
+// negotiateFormat elige el formato de la respuesta entre formats: ?format= tiene
+// prioridad sobre el header Accept. El primero de formats es el formato por defecto.
+func negotiateFormat(c *gin.Context, formats ...string) (string, error) {
+	if format := c.Query("format"); format != "" {
+		for _, f := range formats {
+			if f == format {
+				return f, nil
+			}
+		}
+		return "", usecases.NewValidationError("validation.format", format, strings.Join(formats, ", "))
+	}
+
+	offered := make([]string, len(formats))
+	for i, f := range formats {
+		offered[i] = formatMIME[f]
+	}
+	mime := c.NegotiateFormat(offered...)
+	for i, m := range offered {
+		if m == mime {
+			return formats[i], nil
+		}
+	}
+	return formats[0], nil
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
-	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
 )
 
@@ -14,10 +13,7 @@ func HealthCheck(healthUseCase usecases.HealthUseCase) gin.HandlerFunc {
 		ctx := log.With(c.Request.Context(), log.UseCase("health"))
 		response, err := healthUseCase.CheckHealth(ctx)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "Error verificando salud del sistema",
-				Details: err.Error(),
-			})
+			respondError(ctx, c, "error.health", err)
 			return
 		}
 		
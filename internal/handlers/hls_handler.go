@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+// ServeHLS sirve las playlists y segmentos de la rendition HLS local del video, con
+// soporte de Range y solicitudes condicionales. Las playlists y los segmentos usan
+// distinto Cache-Control.
+func ServeHLS(videoUseCase usecases.VideoUseCase, playlistCacheControl, segmentCacheControl string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("serve_hls"))
+
+		file, err := videoUseCase.GetHLSFile(ctx, c.Param("id"), c.Param("path"))
+		if err != nil {
+			respondError(ctx, c, "error.video", err)
+			return
+		}
+
+		f, err := os.Open(file.Path)
+		if err != nil {
+			respondError(ctx, c, "error.video", usecases.NewInternalError(err, "error abriendo archivo HLS"))
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			respondError(ctx, c, "error.video", usecases.NewInternalError(err, "error abriendo archivo HLS"))
+			return
+		}
+
+		cacheControl := segmentCacheControl
+		if file.Playlist {
+			cacheControl = playlistCacheControl
+		}
+		c.Header("Content-Type", file.ContentType)
+		c.Header("Cache-Control", cacheControl)
+		c.Header("ETag", fileETag(info))
+		http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), f)
+	}
+}
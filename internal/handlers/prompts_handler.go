@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+// GetPromptVersions lista las versiones de templates de prompt cargadas
+func GetPromptVersions(promptUseCase usecases.PromptUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("get_prompts"))
+
+		versions, err := promptUseCase.GetVersions(ctx)
+		if err != nil {
+			respondError(ctx, c, "error.prompts", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, versions)
+	}
+}
+
+// ReloadPrompts vuelve a leer los templates de prompt desde disco
+func ReloadPrompts(promptUseCase usecases.PromptUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("reload_prompts"))
+
+		versions, err := promptUseCase.Reload(ctx)
+		if err != nil {
+			respondError(ctx, c, "error.prompts", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, versions)
+	}
+}
@@ -15,24 +15,14 @@ func Search(searchUseCase usecases.SearchUseCase) gin.HandlerFunc {
 
 		var req models.SearchRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error: "Query required",
-			})
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.invalid_body"))
 			return
 		}
 
-		// Establecer top_k por defecto si no se especifica
-		if req.TopK == 0 {
-			req.TopK = 10 // Valor por defecto
-		}
-
-		// Realizar búsqueda usando el use case
-		response, err := searchUseCase.Search(ctx, req.Query, req.TopK)
+		// Realizar búsqueda usando el use case; las opciones omitidas toman el valor por defecto
+		response, err := searchUseCase.Search(ctx, req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "error searching",
-				Details: err.Error(),
-			})
+			respondError(ctx, c, "error.search", err)
 			return
 		}
 
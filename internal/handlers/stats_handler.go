@@ -5,7 +5,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
-	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
 )
 
@@ -15,10 +14,7 @@ func GetStats(statsUseCase usecases.StatsUseCase) gin.HandlerFunc {
 
 		stats, err := statsUseCase.GetStats(ctx)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "Error obteniendo estadísticas",
-				Details: err.Error(),
-			})
+			respondError(ctx, c, "error.stats", err)
 			return
 		}
 
@@ -1,65 +1,82 @@
 package handlers
 
 import (
-	"io"
+	"bytes"
 	"net/http"
 	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
-	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/subtitles"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
 )
 
-// ServeSubtitles retorna un handler para servir subtítulos
+// ServeSubtitles retorna un handler para servir subtítulos en WebVTT (por defecto), SRT,
+// texto plano o JSON. El idioma se elige con ?language= o Accept-Language. Si el formato
+// pedido es el del archivo se sirve sin convertir.
 func ServeSubtitles(videoUseCase usecases.VideoUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := log.With(c.Request.Context(), log.UseCase("get_subtitles"))
 
 		id := c.Param("id")
 		if id == "" {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error: "id parameter is required",
-			})
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.id_required"))
 			return
 		}
 
-		subtitlePath, err := videoUseCase.GetSubtitles(ctx, id)
+		format, err := negotiateFormat(c, subtitles.FormatVTT, subtitles.FormatSRT, subtitles.FormatText, subtitles.FormatJSON)
 		if err != nil {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
-				Error:   "Subtitle file not found",
-				Details: err.Error(),
-			})
+			respondError(ctx, c, "error.invalid_request", err)
 			return
 		}
 
-		// Leer el contenido del archivo para evitar problemas de cache
-		file, err := os.Open(subtitlePath)
+		track, err := videoUseCase.GetSubtitles(ctx, id, c.Query("language"), c.GetHeader("Accept-Language"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "Error reading subtitle file",
-				Details: err.Error(),
-			})
+			respondError(ctx, c, "error.subtitles", err)
 			return
 		}
-		defer file.Close()
 
-		content, err := io.ReadAll(file)
+		var content []byte
+		if subtitles.FormatOf(track.Path) == format {
+			content, err = os.ReadFile(track.Path)
+			if err != nil {
+				respondError(ctx, c, "error.subtitles", usecases.NewInternalError(err, "error leyendo subtítulos"))
+				return
+			}
+		} else {
+			cues, err := subtitles.ReadFile(track.Path)
+			if err != nil {
+				respondError(ctx, c, "error.subtitles", usecases.NewInternalError(err, "error leyendo subtítulos"))
+				return
+			}
+			var buf bytes.Buffer
+			if err := subtitles.Write(&buf, cues, format); err != nil {
+				respondError(ctx, c, "error.subtitles", usecases.NewInternalError(err, "error convirtiendo subtítulos"))
+				return
+			}
+			content = buf.Bytes()
+		}
+
+		if info, err := os.Stat(track.Path); err == nil {
+			setLastModified(c, info.ModTime())
+		}
+		c.Header("Content-Language", track.Language)
+
+		c.Data(http.StatusOK, formatMIME[format]+"; charset=utf-8", content)
+	}
+}
+
+// GetSubtitleTracks lista las pistas de subtítulos disponibles del video
+func GetSubtitleTracks(videoUseCase usecases.VideoUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("get_subtitle_tracks"))
+
+		tracks, err := videoUseCase.GetSubtitleTracks(ctx, c.Param("id"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "Error reading subtitle content",
-				Details: err.Error(),
-			})
+			respondError(ctx, c, "error.subtitles", err)
 			return
 		}
 
-		// Headers para evitar cache
-		c.Header("Content-Type", "text/vtt; charset=utf-8")
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
-		c.Header("Pragma", "no-cache")
-		c.Header("Expires", "0")
-
-		c.String(http.StatusOK, string(content))
+		c.JSON(http.StatusOK, tracks)
 	}
 }
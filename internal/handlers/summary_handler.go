@@ -1,42 +1,116 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
 )
 
-func ServeSummary(videoUseCase usecases.VideoUseCase) gin.HandlerFunc {
+// ServeSummary sirve el resumen del video como texto plano (por defecto), JSON o Markdown
+func ServeSummary(summaryUseCase usecases.SummaryUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := log.With(c.Request.Context(), log.UseCase("get_summary"))
 
 		id := c.Param("id")
 		if id == "" {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error: "id parameter is required",
-			})
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.id_required"))
 			return
 		}
 
-		summary, err := videoUseCase.GetSummary(ctx, id)
+		format, err := negotiateFormat(c, "text", "json", "markdown")
 		if err != nil {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
-				Error:   "Summary file not found",
-				Details: err.Error(),
-			})
+			respondError(ctx, c, "error.invalid_request", err)
 			return
 		}
 
-		// Headers para evitar cache
-		c.Header("Content-Type", "text/plain; charset=utf-8")
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
-		c.Header("Pragma", "no-cache")
-		c.Header("Expires", "0")
+		summary, err := summaryUseCase.GetSummary(ctx, id)
+		if err != nil {
+			respondError(ctx, c, "error.summary", err)
+			return
+		}
+
+		if summary.GeneratedAt != nil {
+			setLastModified(c, *summary.GeneratedAt)
+		}
+		respondSummary(ctx, c, format, summary)
+	}
+}
+
+// RegenerateSummary genera el resumen del video desde la transcripción y reemplaza el guardado
+func RegenerateSummary(summaryUseCase usecases.SummaryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("regenerate_summary"))
+
+		summary, err := summaryUseCase.Regenerate(ctx, c.Param("id"))
+		if err != nil {
+			respondError(ctx, c, "error.summary", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, summary)
+	}
+}
 
-		c.String(http.StatusOK, summary)
+// respondSummary escribe el resumen en el formato elegido
+func respondSummary(ctx context.Context, c *gin.Context, format string, summary *models.VideoSummary) {
+	switch format {
+	case "json":
+		c.JSON(http.StatusOK, summary)
+	case "markdown":
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(summaryMarkdown(ctx, summary)))
+	default:
+		// Un resumen escrito a mano se sirve tal cual
+		text := summary.TLDR
+		if summary.Source == models.SummarySourceGenerated {
+			text = summaryText(ctx, summary)
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(text))
+	}
+}
+
+// summaryMarkdown arma el resumen en Markdown con los encabezados en el idioma de la solicitud
+func summaryMarkdown(ctx context.Context, summary *models.VideoSummary) string {
+	var b strings.Builder
+	if summary.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", summary.Title)
+	}
+	fmt.Fprintf(&b, "## %s\n\n%s\n", i18n.T(ctx, "summary.tldr"), summary.TLDR)
+
+	if len(summary.KeyPoints) > 0 {
+		fmt.Fprintf(&b, "\n## %s\n\n", i18n.T(ctx, "summary.key_points"))
+		for _, point := range summary.KeyPoints {
+			fmt.Fprintf(&b, "- **%s** %s\n", point.Timestamp, point.Text)
+		}
+	}
+	if len(summary.Topics) > 0 {
+		fmt.Fprintf(&b, "\n## %s\n\n", i18n.T(ctx, "summary.topics"))
+		for _, topic := range summary.Topics {
+			fmt.Fprintf(&b, "- %s\n", topic)
+		}
+	}
+	return b.String()
+}
+
+// summaryText arma el resumen en texto plano
+func summaryText(ctx context.Context, summary *models.VideoSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", summary.TLDR)
+
+	if len(summary.KeyPoints) > 0 {
+		fmt.Fprintf(&b, "\n%s:\n", i18n.T(ctx, "summary.key_points"))
+		for _, point := range summary.KeyPoints {
+			fmt.Fprintf(&b, "[%s] %s\n", point.Timestamp, point.Text)
+		}
+	}
+	if len(summary.Topics) > 0 {
+		fmt.Fprintf(&b, "\n%s: %s\n", i18n.T(ctx, "summary.topics"), strings.Join(summary.Topics, ", "))
 	}
+	return b.String()
 }
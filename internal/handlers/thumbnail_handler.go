@@ -1,42 +1,73 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
 )
 
-// ServeThumbnail retorna un handler para servir miniaturas
-func ServeThumbnail(videoUseCase usecases.VideoUseCase) gin.HandlerFunc {
+// ServeThumbnail retorna un handler para servir miniaturas en JPEG (por defecto) o PNG,
+// redimensionadas con ?size= o ?width= y ?height=
+func ServeThumbnail(thumbnailUseCase usecases.ThumbnailUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := log.With(c.Request.Context(), log.UseCase("serve_thumbnail"))
 
-		id := c.Param("id")
+		format, err := negotiateFormat(c, services.ImageJPEG, services.ImagePNG)
+		if err != nil {
+			respondError(ctx, c, "error.invalid_request", err)
+			return
+		}
+
+		thumbnail, file, err := openThumbnail(ctx, thumbnailUseCase, c.Param("id"), models.ThumbnailQuery{
+			Size:   c.Query("size"),
+			Width:  c.Query("width"),
+			Height: c.Query("height"),
+			Format: format,
+		})
+		if err != nil {
+			respondError(ctx, c, "error.thumbnail", err)
+			return
+		}
 
-		thumbnailPath, err := videoUseCase.GetThumbnail(ctx, id)
+		if thumbnail.Content != nil {
+			c.Data(http.StatusOK, thumbnail.ContentType, thumbnail.Content)
+			return
+		}
+		defer file.Close()
+		info, err := file.Stat()
 		if err != nil {
-			// Si no existe, devolver un placeholder SVG simple
-			svgPlaceholder := `<svg width="320" height="180" xmlns="http://www.w3.org/2000/svg">
-				<rect width="320" height="180" fill="#374151"/>
-				<text x="160" y="90" text-anchor="middle" fill="white" font-family="Arial" font-size="24">🎥</text>
-			</svg>`
-
-			c.Header("Content-Type", "image/svg+xml")
-			c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
-			c.Header("Pragma", "no-cache")
-			c.Header("Expires", "0")
-			c.Header("Access-Control-Allow-Origin", "*")
-			c.String(http.StatusOK, svgPlaceholder)
+			respondError(ctx, c, "error.thumbnail", usecases.NewInternalError(err, "error abriendo miniatura"))
 			return
 		}
 
-		c.Header("Content-Type", "image/jpeg")
-		c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
-		c.Header("Pragma", "no-cache")
-		c.Header("Expires", "0")
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.File(thumbnailPath)
+		c.Header("Content-Type", thumbnail.ContentType)
+		http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), file)
+	}
+}
+
+// openThumbnail resuelve la miniatura y abre su archivo. Una variante puede descartarse
+// del cache entre que se resuelve y se abre: en ese caso se pide de nuevo, lo que la
+// regenera. Una vez abierto, el archivo se puede servir aunque se borre.
+func openThumbnail(ctx context.Context, thumbnailUseCase usecases.ThumbnailUseCase, id string, query models.ThumbnailQuery) (*models.Thumbnail, *os.File, error) {
+	for attempt := 0; ; attempt++ {
+		thumbnail, err := thumbnailUseCase.GetThumbnail(ctx, id, query)
+		if err != nil || thumbnail.Content != nil {
+			return thumbnail, nil, err
+		}
+
+		file, err := os.Open(thumbnail.Path)
+		if err == nil {
+			return thumbnail, file, nil
+		}
+		if !os.IsNotExist(err) || attempt > 0 {
+			return nil, nil, usecases.NewInternalError(err, "error abriendo miniatura")
+		}
+		log.Debug(ctx, "Variante de miniatura descartada antes de servirse, se regenera", log.String("path", thumbnail.Path))
 	}
 }
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// fakeThumbnails retorna las miniaturas indicadas en orden, una por llamada
+type fakeThumbnails struct {
+	results []*models.Thumbnail
+	calls   int
+}
+
+func (f *fakeThumbnails) GetThumbnail(ctx context.Context, id string, query models.ThumbnailQuery) (*models.Thumbnail, error) {
+	if f.calls >= len(f.results) {
+		return nil, errors.New("sin miniaturas")
+	}
+	f.calls++
+	return f.results[f.calls-1], nil
+}
+
+func TestOpenThumbnail(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "320x180-1.jpeg")
+	if err := os.WriteFile(existing, []byte("jpeg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	evicted := filepath.Join(dir, "320x180-0.jpeg")
+
+	tests := []struct {
+		name      string
+		results   []*models.Thumbnail
+		wantCalls int
+		wantPath  string
+		wantErr   bool
+	}{
+		{name: "variante en cache", results: []*models.Thumbnail{{Path: existing}}, wantCalls: 1, wantPath: existing},
+		{name: "descartada antes de abrirse", results: []*models.Thumbnail{{Path: evicted}, {Path: existing}}, wantCalls: 2, wantPath: existing},
+		{name: "descartada dos veces", results: []*models.Thumbnail{{Path: evicted}, {Path: evicted}, {Path: existing}}, wantCalls: 2, wantErr: true},
+		{name: "contenido en memoria", results: []*models.Thumbnail{{Content: []byte("<svg/>")}}, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeThumbnails{results: tt.results}
+			thumbnail, file, err := openThumbnail(context.Background(), uc, "video", models.ThumbnailQuery{})
+			if uc.calls != tt.wantCalls {
+				t.Errorf("llamadas = %d, want %d", uc.calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openThumbnail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tt.wantPath == "" {
+				if file != nil {
+					t.Errorf("se abrió un archivo para contenido en memoria")
+				}
+				return
+			}
+			defer file.Close()
+			if thumbnail.Path != tt.wantPath || file.Name() != tt.wantPath {
+				t.Errorf("path = %q (archivo %q), want %q", thumbnail.Path, file.Name(), tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+// ServeTranscript sirve la transcripción del video en párrafos como JSON (por defecto),
+// texto plano o Markdown. Acepta ?language=, ?from=, ?to= y ?highlight=.
+func ServeTranscript(videoUseCase usecases.VideoUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("get_transcript"))
+
+		id := c.Param("id")
+		if id == "" {
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.id_required"))
+			return
+		}
+
+		format, err := negotiateFormat(c, "json", "text", "markdown")
+		if err != nil {
+			respondError(ctx, c, "error.invalid_request", err)
+			return
+		}
+
+		transcript, err := videoUseCase.GetTranscript(ctx, id, models.TranscriptQuery{
+			Language:       c.Query("language"),
+			AcceptLanguage: c.GetHeader("Accept-Language"),
+			From:           c.Query("from"),
+			To:             c.Query("to"),
+			Highlight:      c.Query("highlight"),
+		})
+		if err != nil {
+			respondError(ctx, c, "error.transcript", err)
+			return
+		}
+
+		c.Header("Content-Language", transcript.Language)
+		switch format {
+		case "json":
+			c.JSON(http.StatusOK, transcript)
+		case "markdown":
+			c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(transcriptText(transcript, "**")))
+		default:
+			c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(transcriptText(transcript, "")))
+		}
+	}
+}
+
+// transcriptText arma un párrafo por bloque precedido de su timestamp. Si mark no
+// está vacío, las palabras marcadas se encierran entre mark.
+func transcriptText(transcript *models.TranscriptResponse, mark string) string {
+	var b strings.Builder
+	for i, paragraph := range transcript.Paragraphs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		text := paragraph.Text
+		if mark != "" {
+			text = markRanges(text, paragraph.Highlights, mark)
+		}
+		fmt.Fprintf(&b, "[%s] %s\n", paragraph.Timestamp, text)
+	}
+	return b.String()
+}
+
+// markRanges encierra entre mark los tramos de text, dados en caracteres
+func markRanges(text string, ranges []models.TextRange, mark string) string {
+	if len(ranges) == 0 {
+		return text
+	}
+
+	runes := []rune(text)
+	var b strings.Builder
+	last := 0
+	for _, r := range ranges {
+		b.WriteString(string(runes[last:r.Start]))
+		b.WriteString(mark + string(runes[r.Start:r.End]) + mark)
+		last = r.End
+	}
+	b.WriteString(string(runes[last:]))
+	return b.String()
+}
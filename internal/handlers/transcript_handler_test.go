@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+func TestMarkRanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		ranges []models.TextRange
+		mark   string
+		want   string
+	}{
+		{name: "sin tramos", text: "hola mundo", ranges: nil, mark: "**", want: "hola mundo"},
+		{name: "un tramo", text: "hola mundo", ranges: []models.TextRange{{Start: 5, End: 10}}, mark: "**", want: "hola **mundo**"},
+		{
+			name:   "varios tramos",
+			text:   "go y go",
+			ranges: []models.TextRange{{Start: 0, End: 2}, {Start: 5, End: 7}},
+			mark:   "==",
+			want:   "==go== y ==go==",
+		},
+		{
+			name:   "posiciones en caracteres con acentos",
+			text:   "La sesión ñandú",
+			ranges: []models.TextRange{{Start: 3, End: 9}, {Start: 10, End: 15}},
+			mark:   "**",
+			want:   "La **sesión** **ñandú**",
+		},
+		{name: "tramo al inicio", text: "Ñu", ranges: []models.TextRange{{Start: 0, End: 2}}, mark: "*", want: "*Ñu*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markRanges(tt.text, tt.ranges, tt.mark); got != tt.want {
+				t.Errorf("markRanges(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
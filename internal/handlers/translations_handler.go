@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+// StartTranslation lanza un job que traduce una pista de subtítulos del video a otro idioma
+func StartTranslation(translationUseCase usecases.TranslationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("start_translation"))
+
+		var req models.TranslationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.translation_body"))
+			return
+		}
+
+		job, err := translationUseCase.Translate(ctx, c.Param("id"), req)
+		if err != nil {
+			respondError(ctx, c, "error.translation", err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, job)
+	}
+}
+
+// GetTranslationJob retorna el estado de un job de traducción
+func GetTranslationJob(translationUseCase usecases.TranslationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("get_translation_job"))
+
+		job, err := translationUseCase.GetJob(ctx, c.Param("job_id"))
+		if err != nil {
+			respondError(ctx, c, "error.translation", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// ListTranslationJobs lista los jobs de traducción recientes
+func ListTranslationJobs(translationUseCase usecases.TranslationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("list_translation_jobs"))
+
+		jobs, err := translationUseCase.GetJobs(ctx)
+		if err != nil {
+			respondError(ctx, c, "error.translation", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, jobs)
+	}
+}
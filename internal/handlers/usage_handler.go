@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
+)
+
+// GetUsage retorna el uso agregado del ledger en JSON o CSV.
+// Parámetros: from y to (YYYY-MM-DD, inclusivos), group_by (lista separada por comas) y format.
+func GetUsage(usageUseCase usecases.UsageUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := log.With(c.Request.Context(), log.UseCase("get_usage"))
+
+		now := time.Now().UTC()
+		from, err := parseDay(c.Query("from"), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.date_format", "from"))
+			return
+		}
+
+		to, err := parseDay(c.Query("to"), time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.date_format", "to"))
+			return
+		}
+		if from.After(to) {
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.date_range"))
+			return
+		}
+
+		groupBy := []string{"day"}
+		if raw := c.Query("group_by"); raw != "" {
+			groupBy = strings.Split(raw, ",")
+		}
+
+		// to es inclusivo: se consulta hasta el inicio del día siguiente
+		report, err := usageUseCase.GetReport(ctx, from, to.AddDate(0, 0, 1), groupBy)
+		if err != nil {
+			respondError(ctx, c, "error.usage", err)
+			return
+		}
+
+		if c.Query("format") == "csv" {
+			filename := fmt.Sprintf("usage_%s_%s.csv", report.From, report.To)
+			c.Header("Content-Disposition", "attachment; filename="+filename)
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Status(http.StatusOK)
+			if err := writeUsageCSV(c.Writer, report); err != nil {
+				log.Error(ctx, "Error escribiendo CSV de uso", log.Err(err))
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+func parseDay(value string, defaultValue time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// writeUsageCSV escribe una fila por grupo con las columnas de agrupación seguidas de los totales
+func writeUsageCSV(w http.ResponseWriter, report *models.UsageReportResponse) error {
+	writer := csv.NewWriter(w)
+
+	header := append([]string{}, report.GroupBy...)
+	header = append(header, "requests", "calls", "prompt_tokens", "completion_tokens", "total_tokens", "cost_usd")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		record := make([]string, 0, len(header))
+		for _, group := range report.GroupBy {
+			switch group {
+			case "day":
+				record = append(record, row.Day)
+			case "model":
+				record = append(record, row.Model)
+			case "route":
+				record = append(record, row.Route)
+			case "api_key":
+				record = append(record, row.APIKey)
+			}
+		}
+		record = append(record,
+			strconv.Itoa(row.Requests),
+			strconv.Itoa(row.Calls),
+			strconv.Itoa(row.PromptTokens),
+			strconv.Itoa(row.CompletionTokens),
+			strconv.Itoa(row.TotalTokens),
+			strconv.FormatFloat(row.CostUSD, 'f', 6, 64),
+		)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
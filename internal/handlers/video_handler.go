@@ -1,38 +1,36 @@
 package handlers
 
 import (
-	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
-	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/usecases"
 )
 
-func ServeVideo(videoUseCase usecases.VideoUseCase) gin.HandlerFunc {
+// ServeVideo sirve video.mp4, la fuente MP4 local del video
+func ServeVideo(videoUseCase usecases.VideoUseCase, cacheControl string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := log.With(c.Request.Context(), log.UseCase("serve_video"))
 
 		id := c.Param("id")
 		if id == "" {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error: "id parameter is required",
-			})
+			respondError(ctx, c, "error.invalid_request", usecases.NewValidationError("validation.id_required"))
 			return
 		}
 
 		videoPath, err := videoUseCase.GetVideo(ctx, id)
 		if err != nil {
-			c.JSON(http.StatusNotFound, models.ErrorResponse{
-				Error:   "Video file not found",
-				Details: err.Error(),
-			})
+			respondError(ctx, c, "error.video", err)
 			return
 		}
 
 		c.Header("Content-Type", "video/mp4")
 		c.Header("Accept-Ranges", "bytes")
-		c.Header("Cache-Control", "public, max-age=3600")
+		c.Header("Cache-Control", cacheControl)
+		if info, err := os.Stat(videoPath); err == nil {
+			c.Header("ETag", fileETag(info))
+		}
 		c.File(videoPath)
 	}
 }
@@ -0,0 +1,172 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Idiomas soportados
+const (
+	Spanish = "es"
+	English = "en"
+)
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// catalogs contiene los mensajes por idioma y por key
+var catalogs = mustLoadCatalogs()
+
+// fallback es el idioma usado cuando no se puede negociar otro
+var fallback = Spanish
+
+type langCtxKey struct{}
+
+func mustLoadCatalogs() map[string]map[string]string {
+	files, err := localesFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: no se pudieron leer los catálogos: %v", err))
+	}
+
+	result := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := localesFS.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: no se pudo leer %s: %v", file.Name(), err))
+		}
+
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: catálogo inválido %s: %v", file.Name(), err))
+		}
+		result[strings.TrimSuffix(file.Name(), ".json")] = catalog
+	}
+
+	return result
+}
+
+// Supported indica si hay catálogo para el idioma
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Languages retorna los idiomas con catálogo, ordenados
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// SetFallback define el idioma por defecto. Retorna error si no está soportado.
+func SetFallback(lang string) error {
+	if !Supported(lang) {
+		return fmt.Errorf("idioma no soportado: %s", lang)
+	}
+	fallback = lang
+	return nil
+}
+
+// Fallback retorna el idioma por defecto
+func Fallback() string {
+	return fallback
+}
+
+// WithLanguage retorna un contexto con el idioma de la solicitud
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, langCtxKey{}, lang)
+}
+
+// Language retorna el idioma del contexto o el idioma por defecto
+func Language(ctx context.Context) string {
+	if lang, ok := ctx.Value(langCtxKey{}).(string); ok {
+		return lang
+	}
+	return fallback
+}
+
+// T traduce una key al idioma del contexto
+func T(ctx context.Context, key string, args ...interface{}) string {
+	return Translate(Language(ctx), key, args...)
+}
+
+// Translate traduce una key a un idioma. Si falta en ese idioma se usa el idioma
+// por defecto, y si tampoco existe se retorna la key.
+func Translate(lang, key string, args ...interface{}) string {
+	message, ok := catalogs[lang][key]
+	if !ok {
+		message, ok = catalogs[fallback][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// Negotiate elige el idioma: primero el parámetro explícito, luego Accept-Language
+// (respetando los pesos q) y por último el idioma por defecto
+func Negotiate(explicit, acceptLanguage string) string {
+	if lang := normalize(explicit); Supported(lang) {
+		return lang
+	}
+
+	for _, lang := range ParseAcceptLanguage(acceptLanguage) {
+		if Supported(lang) {
+			return lang
+		}
+	}
+
+	return fallback
+}
+
+// ParseAcceptLanguage retorna los idiomas del header Accept-Language ordenados por
+// peso q, reducidos a su subetiqueta primaria y sin los de peso 0
+func ParseAcceptLanguage(acceptLanguage string) []string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if lang := normalize(tag); lang != "" && lang != "*" && q > 0 {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	langs := make([]string, len(candidates))
+	for i, c := range candidates {
+		langs[i] = c.lang
+	}
+	return langs
+}
+
+// normalize reduce una etiqueta de idioma a su subetiqueta primaria ("en-US" -> "en")
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	primary, _, _ := strings.Cut(tag, "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	return primary
+}
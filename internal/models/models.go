@@ -1,8 +1,53 @@
 package models
 
+import "time"
+
+// SearchRequest es el cuerpo de una búsqueda. Las opciones omitidas toman el valor
+// por defecto del servidor.
 type SearchRequest struct {
-	Query string `json:"query" binding:"required,min=2"`
-	TopK  int    `json:"top_k"`
+	Query           string   `json:"query" binding:"required,min=2"`
+	TopK            int      `json:"top_k"`
+	PromptVersion   string   `json:"prompt_version,omitempty"`
+	GenerateAnswer  *bool    `json:"generate_answer,omitempty"`
+	Style           string   `json:"style,omitempty"`
+	MinScore        *float64 `json:"min_score,omitempty"`
+	MaxAnswerTokens int      `json:"max_answer_tokens,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	IncludeText     *bool    `json:"include_text,omitempty"`
+	ExpandQuery     *bool    `json:"expand_query,omitempty"`
+	Expansions      int      `json:"expansions,omitempty"`
+	RetrievalMode   string   `json:"retrieval_mode,omitempty"`
+	HyDEAverage     *bool    `json:"hyde_average,omitempty"`
+}
+
+// SearchOptions son las opciones efectivas de una búsqueda, después de aplicar
+// los valores por defecto y los límites del servidor
+type SearchOptions struct {
+	TopK            int     `json:"top_k"`
+	GenerateAnswer  bool    `json:"generate_answer"`
+	Style           string  `json:"style"`
+	MinScore        float64 `json:"min_score"`
+	MaxAnswerTokens int     `json:"max_answer_tokens"`
+	Language        string  `json:"language"`
+	IncludeText     bool    `json:"include_text"`
+	ExpandQuery     bool    `json:"expand_query"`
+	Expansions      int     `json:"expansions"`
+	RetrievalMode   string  `json:"retrieval_mode"`
+	HyDEAverage     bool    `json:"hyde_average"`
+}
+
+// HyDEPassage es el fragmento hipotético usado para buscar en modo HyDE y su costo
+// adicional (chat más la parte estimada de los embeddings)
+type HyDEPassage struct {
+	Passage string  `json:"passage"`
+	CostUSD float64 `json:"cost_usd"`
+}
+
+// QueryExpansion describe las reformulaciones usadas en la búsqueda y su costo
+// adicional (chat más la parte estimada de los embeddings)
+type QueryExpansion struct {
+	Variants []string `json:"variants"`
+	CostUSD  float64  `json:"cost_usd"`
 }
 
 type ChunkResponse struct {
@@ -22,6 +67,26 @@ type SearchResponse struct {
 	Total           int             `json:"total"`
 	GeneratedAnswer string          `json:"generated_answer,omitempty"`
 	CostoUSD        float64         `json:"costo_usd,omitempty"`
+	BudgetStatus    string          `json:"budget_status,omitempty"`
+	Options         SearchOptions   `json:"options"`
+	Expansion       *QueryExpansion `json:"expansion,omitempty"`
+	HyDE            *HyDEPassage    `json:"hyde,omitempty"`
+	Metadata        *SearchMetadata `json:"metadata,omitempty"`
+}
+
+// SearchMetadata describe cómo se generó la respuesta
+type SearchMetadata struct {
+	Model            string `json:"model,omitempty"`
+	PromptVersion    string `json:"prompt_version,omitempty"`
+	ContextFragments int    `json:"context_fragments"`
+	ContextTokens    int    `json:"context_tokens"`
+}
+
+// PromptVersionsResponse lista los templates de prompt cargados
+type PromptVersionsResponse struct {
+	DefaultVersion string            `json:"default_version"`
+	Versions       []string          `json:"versions"`
+	PerAPIKey      map[string]string `json:"per_api_key,omitempty"`
 }
 
 type StatsResponse struct {
@@ -31,25 +96,50 @@ type StatsResponse struct {
 	Modelo        string `json:"modelo"`
 }
 
+type BudgetLimits struct {
+	DailySoftUSD   float64 `json:"daily_soft_usd,omitempty"`
+	DailyHardUSD   float64 `json:"daily_hard_usd,omitempty"`
+	MonthlySoftUSD float64 `json:"monthly_soft_usd,omitempty"`
+	MonthlyHardUSD float64 `json:"monthly_hard_usd,omitempty"`
+}
+
+type BudgetUsage struct {
+	Status     string       `json:"status"`
+	Day        string       `json:"day"`
+	DailyUSD   float64      `json:"daily_usd"`
+	Month      string       `json:"month"`
+	MonthlyUSD float64      `json:"monthly_usd"`
+	Limits     BudgetLimits `json:"limits"`
+}
+
+type BudgetStatusResponse struct {
+	Global  BudgetUsage            `json:"global"`
+	APIKeys map[string]BudgetUsage `json:"api_keys"`
+}
+
 type HealthResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 }
 
+// ErrorResponse es el cuerpo de toda respuesta de error. Code es un código estable
+// para que los clientes distingan el error sin depender del mensaje.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    int    `json:"code,omitempty"`
-	Details string `json:"details,omitempty"`
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id"`
+	Details   string `json:"details,omitempty"`
 }
 
 type OpenAIEmbeddingRequest struct {
-	Input      string `json:"input"`
-	Model      string `json:"model"`
-	Dimensions int    `json:"dimensions,omitempty"`
+	Input      []string `json:"input"`
+	Model      string   `json:"model"`
+	Dimensions int      `json:"dimensions,omitempty"`
 }
 
 type OpenAIEmbeddingResponse struct {
 	Data []struct {
+		Index     int       `json:"index"`
 		Embedding []float32 `json:"embedding"`
 	} `json:"data"`
 	Usage struct {
@@ -62,7 +152,7 @@ type OpenAIChatRequest struct {
 	Model       string    `json:"model"`
 	Messages    []Message `json:"messages"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Temperature float64   `json:"temperature"`
 }
 
 type Message struct {
@@ -77,12 +167,93 @@ type OpenAIChatResponse struct {
 		} `json:"message"`
 	} `json:"choices"`
 	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
+		PromptTokens        int `json:"prompt_tokens"`
+		CompletionTokens    int `json:"completion_tokens"`
+		TotalTokens         int `json:"total_tokens"`
+		PromptTokensDetails struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"prompt_tokens_details"`
+	} `json:"usage"`
+}
+
+type AnthropicMessagesRequest struct {
+	Model       string    `json:"model"`
+	System      string    `json:"system,omitempty"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature float64   `json:"temperature"`
+}
+
+type AnthropicMessagesResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
 	} `json:"usage"`
 }
 
+// ModelPrice contiene los precios en USD cada 1K tokens de un modelo
+type ModelPrice struct {
+	InputPer1K       float64 `json:"input_per_1k" yaml:"input_per_1k"`
+	CachedInputPer1K float64 `json:"cached_input_per_1k,omitempty" yaml:"cached_input_per_1k,omitempty"`
+	OutputPer1K      float64 `json:"output_per_1k,omitempty" yaml:"output_per_1k,omitempty"`
+}
+
+// GenerationParams son los parámetros de generación de un modelo de chat.
+// ContextTokens es el presupuesto de tokens para los fragmentos de contexto del prompt.
+type GenerationParams struct {
+	MaxTokens     int     `json:"max_tokens" yaml:"max_tokens"`
+	Temperature   float64 `json:"temperature" yaml:"temperature"`
+	ContextTokens int     `json:"context_tokens" yaml:"context_tokens"`
+}
+
+// TokenUsage describe el consumo de una llamada a un modelo
+type TokenUsage struct {
+	Model            string  `json:"model"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CachedTokens     int     `json:"cached_tokens,omitempty"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+// UsageEntry es un registro del ledger de uso
+type UsageEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id"`
+	ClientID  string    `json:"client_id"`
+	APIKey    string    `json:"api_key,omitempty"`
+	Route     string    `json:"route"`
+	Operation string    `json:"operation"`
+	TokenUsage
+}
+
+type UsageRow struct {
+	Day              string  `json:"day,omitempty"`
+	Model            string  `json:"model,omitempty"`
+	Route            string  `json:"route,omitempty"`
+	APIKey           string  `json:"api_key,omitempty"`
+	Requests         int     `json:"requests"` // solicitudes HTTP distintas
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+type UsageReportResponse struct {
+	From    string     `json:"from"`
+	To      string     `json:"to"`
+	GroupBy []string   `json:"group_by"`
+	Rows    []UsageRow `json:"rows"`
+	Total   UsageRow   `json:"total"`
+}
+
 type Video struct {
 	ID          string `json:"id"`
 	Title       string `json:"title"`
@@ -90,8 +261,197 @@ type Video struct {
 	Duration    string `json:"duration"`
 	Description string `json:"description"`
 	URL         string `json:"url"`
+
+	// Formato preferido para reproducir el video (hls o mp4); vacío es hls
+	Prefer string `json:"prefer,omitempty"`
+
+	// Idiomas con pista de subtítulos y fuentes de reproducción, solo en el listado de videos
+	SubtitleLanguages []string      `json:"subtitle_languages,omitempty"`
+	Sources           []VideoSource `json:"sources,omitempty"`
+}
+
+// Tipos de fuente de reproducción
+const (
+	VideoSourceHLS = "hls"
+	VideoSourceMP4 = "mp4"
+)
+
+// VideoSource es una forma de reproducir el video. Las fuentes se listan en orden de
+// preferencia: el reproductor usa la primera que soporte.
+type VideoSource struct {
+	Type     string `json:"type"`
+	URL      string `json:"url"`
+	MIMEType string `json:"mime_type"`
+	Local    bool   `json:"local"` // servida por la API; si no, es la url de videos.json
+}
+
+// HLSFile es un archivo de una rendition HLS local: una playlist o un segmento
+type HLSFile struct {
+	Path        string
+	ContentType string
+	Playlist    bool
 }
 
 type VideosData struct {
 	Videos []Video `json:"videos"`
 }
+
+// ThumbnailQuery son los parámetros de GET /video/:id/thumbnail. Size es un preset
+// de la configuración; si no se indica se usan Width y/o Height.
+type ThumbnailQuery struct {
+	Size   string
+	Width  string
+	Height string
+	Format string
+}
+
+// Thumbnail es la miniatura a servir: un archivo en Path o, para el placeholder
+// genérico, el SVG en Content
+type Thumbnail struct {
+	Path        string
+	Content     []byte
+	ContentType string
+	Placeholder bool
+}
+
+// Orígenes de un resumen de video
+const (
+	SummarySourceGenerated = "generated" // generado desde la transcripción
+	SummarySourceManual    = "manual"    // summary.txt escrito a mano
+)
+
+type VideoSummary struct {
+	VideoID     string         `json:"video_id"`
+	Title       string         `json:"title,omitempty"`
+	Source      string         `json:"source"`
+	TLDR        string         `json:"tldr"`
+	KeyPoints   []SummaryPoint `json:"key_points"`
+	Topics      []string       `json:"topics"`
+	Model       string         `json:"model,omitempty"`
+	Chunks      int            `json:"chunks,omitempty"`
+	CostUSD     float64        `json:"cost_usd,omitempty"`
+	GeneratedAt *time.Time     `json:"generated_at,omitempty"`
+}
+
+type SummaryPoint struct {
+	StartSec  float64 `json:"start_sec"`
+	Timestamp string  `json:"timestamp"`
+	Text      string  `json:"text"`
+}
+
+// Orígenes de los capítulos de un video
+const (
+	ChaptersSourceGenerated = "generated" // detectados desde la transcripción
+	ChaptersSourceEdited    = "edited"    // editados con la API
+)
+
+type VideoChapters struct {
+	VideoID     string     `json:"video_id"`
+	Source      string     `json:"source"`
+	Chapters    []Chapter  `json:"chapters"`
+	Model       string     `json:"model,omitempty"`
+	CostUSD     float64    `json:"cost_usd,omitempty"`
+	GeneratedAt *time.Time `json:"generated_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type Chapter struct {
+	StartSec  float64 `json:"start_sec"`
+	EndSec    float64 `json:"end_sec"`
+	Timestamp string  `json:"timestamp"`
+	Title     string  `json:"title"`
+}
+
+// ChaptersUpdateRequest reemplaza los capítulos de un video. El fin de cada capítulo
+// es el inicio del siguiente, y el del último es el fin del video.
+type ChaptersUpdateRequest struct {
+	Chapters []ChapterInput `json:"chapters" binding:"required"`
+}
+
+type ChapterInput struct {
+	StartSec float64 `json:"start_sec"`
+	Title    string  `json:"title"`
+}
+
+// SubtitleTrack es una pista de subtítulos de un video en un idioma
+type SubtitleTrack struct {
+	Language string `json:"language"`
+	Format   string `json:"format"`  // formato del archivo: vtt o srt
+	Default  bool   `json:"default"` // pista en el idioma original del video
+	URL      string `json:"url"`
+	Path     string `json:"-"`
+}
+
+type SubtitleTracksResponse struct {
+	VideoID string          `json:"video_id"`
+	Tracks  []SubtitleTrack `json:"tracks"`
+}
+
+// TranscriptQuery son los parámetros de GET /video/:id/transcript. From y To aceptan
+// segundos ("90") o timestamps ("1:30", "00:01:30.000").
+type TranscriptQuery struct {
+	Language       string
+	AcceptLanguage string
+	From           string
+	To             string
+	Highlight      string
+}
+
+// TranscriptResponse es la transcripción del video armada en párrafos
+type TranscriptResponse struct {
+	VideoID    string                `json:"video_id"`
+	Language   string                `json:"language"`
+	FromSec    float64               `json:"from_sec"`
+	ToSec      float64               `json:"to_sec"`
+	Paragraphs []TranscriptParagraph `json:"paragraphs"`
+}
+
+type TranscriptParagraph struct {
+	StartSec   float64     `json:"start_sec"`
+	EndSec     float64     `json:"end_sec"`
+	Timestamp  string      `json:"timestamp"`
+	Text       string      `json:"text"`
+	Highlights []TextRange `json:"highlights,omitempty"`
+}
+
+// TextRange es un tramo [Start, End) de un texto, en caracteres
+type TextRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// TranslationRequest pide traducir una pista de subtítulos a otro idioma
+type TranslationRequest struct {
+	TargetLanguage string `json:"target_language" binding:"required"`
+	SourceLanguage string `json:"source_language,omitempty"` // vacío = idioma original
+	Overwrite      bool   `json:"overwrite,omitempty"`       // reemplazar la pista si ya existe
+}
+
+// Estados de un job
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+type TranslationJob struct {
+	ID               string         `json:"id"`
+	VideoID          string         `json:"video_id"`
+	SourceLanguage   string         `json:"source_language"`
+	TargetLanguage   string         `json:"target_language"`
+	Status           string         `json:"status"`
+	Cues             int            `json:"cues"`
+	Batches          int            `json:"batches"`
+	CompletedBatches int            `json:"completed_batches"`
+	Model            string         `json:"model,omitempty"`
+	CostUSD          float64        `json:"cost_usd"`
+	Error            string         `json:"error,omitempty"`
+	Track            *SubtitleTrack `json:"track,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+	FinishedAt       *time.Time     `json:"finished_at,omitempty"`
+}
+
+type TranslationJobsResponse struct {
+	Jobs []TranslationJob `json:"jobs"`
+}
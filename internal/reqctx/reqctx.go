@@ -0,0 +1,65 @@
+// Package reqctx guarda en el contexto los datos de la solicitud HTTP que completan los
+// middlewares (request id, ruta, cliente) y que leen handlers y use cases.
+package reqctx
+
+import "context"
+
+type requestIDKey struct{}
+
+type routeKey struct{}
+
+type clientIDKey struct{}
+
+type apiKeyNameKey struct{}
+
+// WithRequestID agrega el id de la solicitud al contexto
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID retorna el id de la solicitud, o "" si no hay
+func RequestID(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return requestID
+	}
+	return ""
+}
+
+// WithRoute agrega el patrón de la ruta al contexto
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// Route retorna el patrón de la ruta (por ejemplo "/video/:id") de la solicitud
+func Route(ctx context.Context) string {
+	if route, ok := ctx.Value(routeKey{}).(string); ok {
+		return route
+	}
+	return ""
+}
+
+// WithClientID agrega el identificador del cliente ("key:<nombre>" o "ip:<ip>") al contexto
+func WithClientID(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, clientID)
+}
+
+// ClientID retorna el identificador del cliente, o "" si no hay
+func ClientID(ctx context.Context) string {
+	if clientID, ok := ctx.Value(clientIDKey{}).(string); ok {
+		return clientID
+	}
+	return ""
+}
+
+// WithAPIKeyName agrega al contexto el nombre del cliente asociado a la API key
+func WithAPIKeyName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, apiKeyNameKey{}, name)
+}
+
+// APIKeyName retorna el nombre del cliente asociado a la API key, o "" si es anónimo
+func APIKeyName(ctx context.Context) string {
+	if name, ok := ctx.Value(apiKeyNameKey{}).(string); ok {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// ChatRequest es una solicitud de generación: el prompt de sistema ya renderizado y la pregunta.
+// MaxTokens limita la respuesta por debajo del máximo del modelo (0 = máximo del modelo).
+type ChatRequest struct {
+	SystemPrompt string
+	Query        string
+	MaxTokens    int
+}
+
+// maxTokens retorna el límite de tokens de la respuesta para el modelo
+func (r ChatRequest) maxTokens(params models.GenerationParams) int {
+	if r.MaxTokens > 0 && r.MaxTokens < params.MaxTokens {
+		return r.MaxTokens
+	}
+	return params.MaxTokens
+}
+
+// ChatModel genera respuestas con un proveedor de chat. Cada implementación conoce su
+// modelo y sus parámetros de generación.
+type ChatModel interface {
+	// Name es el nombre del proveedor en la configuración
+	Name() string
+	// Model es el modelo usado para generar
+	Model() string
+	// Generate retorna la respuesta y su uso. Con error el uso puede no estar vacío:
+	// son los tokens que el proveedor cobró igual y hay que registrar.
+	Generate(ctx context.Context, req ChatRequest) (string, models.TokenUsage, error)
+}
+
+// Tipos de proveedor de chat
+const (
+	ChatProviderOpenAI    = "openai"
+	ChatProviderAnthropic = "anthropic"
+	ChatProviderEcho      = "echo"
+)
+
+// postJSON envía body como JSON y decodifica la respuesta en out. Un 429 se reporta
+// como ErrQuotaExceeded; cualquier otro status distinto de 200 es un error con el cuerpo.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error llamando a %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %s", ErrQuotaExceeded, url)
+		}
+		return fmt.Errorf("%s retornó status %d: %s", url, resp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decodificando respuesta: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// anthropicVersion es la versión de la Messages API de Anthropic
+const anthropicVersion = "2023-06-01"
+
+// AnthropicChat implementa ChatModel con la Messages API de Anthropic
+type AnthropicChat struct {
+	name    string
+	baseURL string
+	apiKey  string
+	model   string
+	headers map[string]string
+	params  models.GenerationParams
+	pricing Pricing
+	client  *http.Client
+}
+
+// NewAnthropicChat crea un proveedor de la Messages API de Anthropic
+func NewAnthropicChat(name, baseURL, apiKey, model string, headers map[string]string, params models.GenerationParams, pricing Pricing) (*AnthropicChat, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("base URL is required for chat provider %s", name)
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required for chat provider %s", name)
+	}
+	if model == "" {
+		return nil, fmt.Errorf("model is required for chat provider %s", name)
+	}
+	if !pricing.Has(model) {
+		return nil, fmt.Errorf("no price configured for chat model %s", model)
+	}
+
+	return &AnthropicChat{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		headers: headers,
+		params:  params,
+		pricing: pricing,
+		client:  &http.Client{},
+	}, nil
+}
+
+func (a *AnthropicChat) Name() string {
+	return a.name
+}
+
+func (a *AnthropicChat) Model() string {
+	return a.model
+}
+
+// Generate genera una respuesta con el endpoint /v1/messages
+func (a *AnthropicChat) Generate(ctx context.Context, req ChatRequest) (string, models.TokenUsage, error) {
+	reqBody := models.AnthropicMessagesRequest{
+		Model:       a.model,
+		System:      req.SystemPrompt,
+		Messages:    []models.Message{{Role: "user", Content: req.Query}},
+		MaxTokens:   req.maxTokens(a.params),
+		Temperature: a.params.Temperature,
+	}
+
+	headers := map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": anthropicVersion,
+	}
+	for name, value := range a.headers {
+		headers[name] = value
+	}
+
+	var msgResp models.AnthropicMessagesResponse
+	if err := postJSON(ctx, a.client, a.baseURL+"/v1/messages", headers, reqBody, &msgResp); err != nil {
+		return "", models.TokenUsage{}, err
+	}
+
+	var answer strings.Builder
+	for _, block := range msgResp.Content {
+		if block.Type == "text" {
+			answer.WriteString(block.Text)
+		}
+	}
+	// Anthropic informa los tokens leídos de cache aparte de input_tokens
+	promptTokens := msgResp.Usage.InputTokens + msgResp.Usage.CacheReadInputTokens + msgResp.Usage.CacheCreationInputTokens
+	usage := models.TokenUsage{
+		Model:            a.model,
+		PromptTokens:     promptTokens,
+		CachedTokens:     msgResp.Usage.CacheReadInputTokens,
+		CompletionTokens: msgResp.Usage.OutputTokens,
+		TotalTokens:      promptTokens + msgResp.Usage.OutputTokens,
+	}
+	var err error
+	if usage.CostUSD, err = a.pricing.Cost(usage); err != nil {
+		return "", models.TokenUsage{}, err
+	}
+
+	// Una respuesta sin texto igual consumió tokens: se informa el uso con el error
+	if answer.Len() == 0 {
+		return "", usage, fmt.Errorf("no se recibió respuesta de %s", a.name)
+	}
+
+	log.Info(ctx, "Got answer",
+		log.String("provider", a.name),
+		log.Int("prompt_tokens", usage.PromptTokens),
+		log.Int("cached_tokens", usage.CachedTokens),
+		log.Int("completion_tokens", usage.CompletionTokens),
+		log.Float("costo", usage.CostUSD),
+	)
+
+	return answer.String(), usage, nil
+}
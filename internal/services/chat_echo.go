@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// echoMaxLines es la cantidad de líneas del contexto que devuelve EchoChat
+const echoMaxLines = 3
+
+// EchoChat es un ChatModel local para pruebas: no llama a ninguna API y responde con
+// las líneas del prompt que comparten más palabras con la pregunta. No tiene costo.
+type EchoChat struct {
+	name string
+}
+
+// NewEchoChat crea el proveedor local de prueba
+func NewEchoChat(name string) *EchoChat {
+	return &EchoChat{name: name}
+}
+
+func (e *EchoChat) Name() string {
+	return e.name
+}
+
+func (e *EchoChat) Model() string {
+	return ChatProviderEcho
+}
+
+// Generate arma una respuesta extractiva a partir del prompt de sistema
+func (e *EchoChat) Generate(ctx context.Context, req ChatRequest) (string, models.TokenUsage, error) {
+	queryWords := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(req.Query)) {
+		if len(word) > 3 {
+			queryWords[strings.Trim(word, "¿?¡!.,;:\"'")] = true
+		}
+	}
+
+	type scoredLine struct {
+		text  string
+		score int
+	}
+	var lines []scoredLine
+	for _, line := range strings.Split(req.SystemPrompt, "\n") {
+		line = strings.TrimSpace(line)
+		score := 0
+		for _, word := range strings.Fields(strings.ToLower(line)) {
+			if queryWords[strings.Trim(word, "¿?¡!.,;:\"'")] {
+				score++
+			}
+		}
+		if score > 0 {
+			lines = append(lines, scoredLine{text: line, score: score})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].score > lines[j].score
+	})
+
+	answer := "No tengo suficiente información para responder la pregunta."
+	if len(lines) > 0 {
+		selected := make([]string, 0, echoMaxLines)
+		for i := 0; i < len(lines) && i < echoMaxLines; i++ {
+			selected = append(selected, lines[i].text)
+		}
+		answer = strings.Join(selected, "\n")
+	}
+
+	promptTokens := EstimateTokens(req.SystemPrompt) + EstimateTokens(req.Query)
+	completionTokens := EstimateTokens(answer)
+	return answer, models.TokenUsage{
+		Model:            ChatProviderEcho,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	}, nil
+}
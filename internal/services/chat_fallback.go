@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// FallbackChat prueba los proveedores en orden y pasa al siguiente cuando uno
+// falla o no responde dentro del timeout
+type FallbackChat struct {
+	providers []ChatModel
+	timeout   time.Duration
+}
+
+// NewFallbackChat crea una cadena de proveedores. El primero es el principal.
+func NewFallbackChat(providers []ChatModel, timeout time.Duration) (*FallbackChat, error) {
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("at least one chat provider is required")
+	}
+	if timeout <= 0 {
+		return nil, fmt.Errorf("chat timeout must be positive")
+	}
+
+	return &FallbackChat{
+		providers: providers,
+		timeout:   timeout,
+	}, nil
+}
+
+// Name retorna el nombre del proveedor principal
+func (f *FallbackChat) Name() string {
+	return f.providers[0].Name()
+}
+
+// Model retorna el modelo del proveedor principal
+func (f *FallbackChat) Model() string {
+	return f.providers[0].Model()
+}
+
+// Generate retorna la respuesta del primer proveedor que responde. Si todos fallan
+// retorna los errores de cada uno. El uso retornado, aun con error, suma los tokens y
+// el costo que informaron los intentos fallidos.
+func (f *FallbackChat) Generate(ctx context.Context, req ChatRequest) (string, models.TokenUsage, error) {
+	var errs []error
+	var spent models.TokenUsage
+	for i, provider := range f.providers {
+		answer, usage, err := f.generate(ctx, provider, req)
+		spent = addUsage(spent, usage)
+		if err == nil {
+			if i > 0 {
+				log.Warn(ctx, "Respuesta generada por proveedor de respaldo",
+					log.String("provider", provider.Name()),
+					log.String("model", provider.Model()),
+				)
+			}
+			return answer, spent, nil
+		}
+
+		// Si la solicitud fue cancelada no tiene sentido seguir probando
+		if ctx.Err() != nil {
+			return "", spent, ctx.Err()
+		}
+
+		log.Warn(ctx, "Error en proveedor de chat",
+			log.String("provider", provider.Name()),
+			log.Err(err),
+		)
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+
+	return "", spent, fmt.Errorf("todos los proveedores de chat fallaron: %w", errors.Join(errs...))
+}
+
+// addUsage suma a total el uso de un intento. El modelo es el del último intento que
+// informó uso.
+func addUsage(total, usage models.TokenUsage) models.TokenUsage {
+	if usage.Model != "" {
+		total.Model = usage.Model
+	}
+	total.PromptTokens += usage.PromptTokens
+	total.CachedTokens += usage.CachedTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+	total.CostUSD += usage.CostUSD
+	return total
+}
+
+func (f *FallbackChat) generate(ctx context.Context, provider ChatModel, req ChatRequest) (string, models.TokenUsage, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	answer, usage, err := provider.Generate(ctx, req)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "", models.TokenUsage{}, fmt.Errorf("timeout después de %s", f.timeout)
+	}
+	return answer, usage, err
+}
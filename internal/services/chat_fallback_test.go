@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// fakeChat responde siempre lo mismo
+type fakeChat struct {
+	name   string
+	answer string
+	usage  models.TokenUsage
+	err    error
+}
+
+func (f *fakeChat) Name() string  { return f.name }
+func (f *fakeChat) Model() string { return f.name + "-model" }
+func (f *fakeChat) Generate(ctx context.Context, req ChatRequest) (string, models.TokenUsage, error) {
+	return f.answer, f.usage, f.err
+}
+
+func TestFallbackChatUsage(t *testing.T) {
+	failed := &fakeChat{name: "primary", usage: models.TokenUsage{Model: "a", PromptTokens: 100, TotalTokens: 100, CostUSD: 0.01}, err: errors.New("respuesta vacía")}
+	down := &fakeChat{name: "down", err: errors.New("conexión rechazada")}
+	ok := &fakeChat{name: "backup", answer: "hola", usage: models.TokenUsage{Model: "b", PromptTokens: 50, CompletionTokens: 10, TotalTokens: 60, CostUSD: 0.002}}
+
+	tests := []struct {
+		name      string
+		providers []ChatModel
+		wantErr   bool
+		want      models.TokenUsage
+	}{
+		{
+			name:      "el principal responde",
+			providers: []ChatModel{ok, failed},
+			want:      ok.usage,
+		},
+		{
+			name:      "suma el uso del intento fallido",
+			providers: []ChatModel{failed, down, ok},
+			want:      models.TokenUsage{Model: "b", PromptTokens: 150, CompletionTokens: 10, TotalTokens: 160, CostUSD: 0.012},
+		},
+		{
+			name:      "con error informa lo gastado",
+			providers: []ChatModel{failed, down},
+			wantErr:   true,
+			want:      failed.usage,
+		},
+		{
+			name:      "sin uso informado",
+			providers: []ChatModel{down},
+			wantErr:   true,
+			want:      models.TokenUsage{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chat, err := NewFallbackChat(tt.providers, time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, usage, err := chat.Generate(context.Background(), ChatRequest{Query: "q"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if usage != tt.want {
+				t.Errorf("Generate() usage = %+v, want %+v", usage, tt.want)
+			}
+		})
+	}
+}
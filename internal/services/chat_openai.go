@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// OpenAIChat implementa ChatModel con la API de chat completions de OpenAI o de
+// cualquier servidor compatible (base URL y headers configurables)
+type OpenAIChat struct {
+	name    string
+	baseURL string
+	apiKey  string
+	model   string
+	headers map[string]string
+	params  models.GenerationParams
+	pricing Pricing
+	client  *http.Client
+}
+
+// NewOpenAIChat crea un proveedor compatible con OpenAI. apiKey puede ser vacía para
+// servidores locales que no la requieren.
+func NewOpenAIChat(name, baseURL, apiKey, model string, headers map[string]string, params models.GenerationParams, pricing Pricing) (*OpenAIChat, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("base URL is required for chat provider %s", name)
+	}
+	if model == "" {
+		return nil, fmt.Errorf("model is required for chat provider %s", name)
+	}
+	if !pricing.Has(model) {
+		return nil, fmt.Errorf("no price configured for chat model %s", model)
+	}
+
+	return &OpenAIChat{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		headers: headers,
+		params:  params,
+		pricing: pricing,
+		client:  &http.Client{},
+	}, nil
+}
+
+func (o *OpenAIChat) Name() string {
+	return o.name
+}
+
+func (o *OpenAIChat) Model() string {
+	return o.model
+}
+
+// Generate genera una respuesta con el endpoint /chat/completions
+func (o *OpenAIChat) Generate(ctx context.Context, req ChatRequest) (string, models.TokenUsage, error) {
+	reqBody := models.OpenAIChatRequest{
+		Model: o.model,
+		Messages: []models.Message{
+			{Role: "system", Content: req.SystemPrompt},
+			{Role: "user", Content: req.Query},
+		},
+		MaxTokens:   req.maxTokens(o.params),
+		Temperature: o.params.Temperature,
+	}
+
+	headers := make(map[string]string, len(o.headers)+1)
+	if o.apiKey != "" {
+		headers["Authorization"] = "Bearer " + o.apiKey
+	}
+	for name, value := range o.headers {
+		headers[name] = value
+	}
+
+	var chatResp models.OpenAIChatResponse
+	if err := postJSON(ctx, o.client, o.baseURL+"/chat/completions", headers, reqBody, &chatResp); err != nil {
+		return "", models.TokenUsage{}, err
+	}
+
+	usage := models.TokenUsage{
+		Model:            o.model,
+		PromptTokens:     chatResp.Usage.PromptTokens,
+		CachedTokens:     chatResp.Usage.PromptTokensDetails.CachedTokens,
+		CompletionTokens: chatResp.Usage.CompletionTokens,
+		TotalTokens:      chatResp.Usage.TotalTokens,
+	}
+	var err error
+	if usage.CostUSD, err = o.pricing.Cost(usage); err != nil {
+		return "", models.TokenUsage{}, err
+	}
+
+	// Una respuesta sin contenido igual consumió tokens: se informa el uso con el error
+	if len(chatResp.Choices) == 0 {
+		return "", usage, fmt.Errorf("no se recibió respuesta de %s", o.name)
+	}
+	log.Info(ctx, "Got answer",
+		log.String("provider", o.name),
+		log.Int("prompt_tokens", usage.PromptTokens),
+		log.Int("cached_tokens", usage.CachedTokens),
+		log.Int("completion_tokens", usage.CompletionTokens),
+		log.Float("costo", usage.CostUSD),
+	)
+
+	return chatResp.Choices[0].Message.Content, usage, nil
+}
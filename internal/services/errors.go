@@ -0,0 +1,6 @@
+package services
+
+import "errors"
+
+// ErrQuotaExceeded indica que el proveedor rechazó la llamada por cuota o límite de uso
+var ErrQuotaExceeded = errors.New("cuota del proveedor excedida")
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+
+	"golang.org/x/image/draw"
+)
+
+// Formatos de imagen soportados al codificar miniaturas
+const (
+	ImageJPEG = "jpeg"
+	ImagePNG  = "png"
+)
+
+// DecodeImageFile lee una imagen JPEG o PNG
+func DecodeImageFile(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("imagen inválida %s: %v", path, err)
+	}
+	return img, nil
+}
+
+// ResizeImage escala src a width x height. Si falta una dimensión (0) se calcula
+// conservando la proporción; si están las dos, la imagen cubre el tamaño pedido y se
+// recorta centrada.
+func ResizeImage(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW == 0 || srcH == 0 {
+		return src
+	}
+
+	switch {
+	case width == 0 && height == 0:
+		return src
+	case width == 0:
+		width = max(1, int(math.Round(float64(srcW)*float64(height)/float64(srcH))))
+	case height == 0:
+		height = max(1, int(math.Round(float64(srcH)*float64(width)/float64(srcW))))
+	}
+
+	// Recorte de la región de src con la proporción pedida
+	scale := max(float64(width)/float64(srcW), float64(height)/float64(srcH))
+	cropW := min(srcW, int(math.Round(float64(width)/scale)))
+	cropH := min(srcH, int(math.Round(float64(height)/scale)))
+	x := bounds.Min.X + (srcW-cropW)/2
+	y := bounds.Min.Y + (srcH-cropH)/2
+	crop := image.Rect(x, y, x+cropW, y+cropH)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// EncodeImage escribe img en JPEG (con la calidad indicada) o PNG
+func EncodeImage(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case ImageJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case ImagePNG:
+		return png.Encode(w, img)
+	default:
+		return fmt.Errorf("formato de imagen no soportado: %s", format)
+	}
+}
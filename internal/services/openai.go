@@ -12,57 +12,59 @@ import (
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
 )
 
+// OpenAIService genera embeddings con la API de OpenAI. La generación de respuestas
+// está en los ChatModel.
 type OpenAIService struct {
-	APIKey         string
-	Model          string
-	PricePer1K     float64
-	ChatModel      string
-	ChatPricePer1K float64
+	APIKey  string
+	Model   string
+	Pricing Pricing
 }
 
 // NewOpenAIService crea una nueva instancia del servicio OpenAI
-func NewOpenAIService(apiKey, model string, pricePer1K float64, chatModel string, chatPricePer1K float64) (*OpenAIService, error) {
+func NewOpenAIService(apiKey, model string, pricing Pricing) (*OpenAIService, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
 	if model == "" {
 		return nil, fmt.Errorf("model is required")
 	}
-	if pricePer1K <= 0 {
-		return nil, fmt.Errorf("price per 1K is required")
-	}
-	if chatModel == "" {
-		return nil, fmt.Errorf("chat model is required")
-	}
-	if chatPricePer1K <= 0 {
-		return nil, fmt.Errorf("chat price per 1K is required")
+	if !pricing.Has(model) {
+		return nil, fmt.Errorf("no price configured for model %s", model)
 	}
 
 	return &OpenAIService{
-		APIKey:         apiKey,
-		Model:          model,
-		PricePer1K:     pricePer1K,
-		ChatModel:      chatModel,
-		ChatPricePer1K: chatPricePer1K,
+		APIKey:  apiKey,
+		Model:   model,
+		Pricing: pricing,
 	}, nil
 }
 
 // GenerateEmbedding genera un embedding para el texto dado
-func (s *OpenAIService) GenerateEmbedding(ctx context.Context, text string) ([]float32, int, error) {
+func (s *OpenAIService) GenerateEmbedding(ctx context.Context, text string) ([]float32, models.TokenUsage, error) {
+	embeddings, usage, err := s.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, models.TokenUsage{}, err
+	}
+	return embeddings[0], usage, nil
+}
+
+// GenerateEmbeddings genera los embeddings de varios textos en una sola llamada.
+// Los embeddings se retornan en el mismo orden que los textos.
+func (s *OpenAIService) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, models.TokenUsage, error) {
 	reqBody := models.OpenAIEmbeddingRequest{
-		Input:      text,
+		Input:      texts,
 		Model:      s.Model,
 		Dimensions: 512, // Usar constante del config
 	}
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error marshaling request: %v", err)
+		return nil, models.TokenUsage{}, fmt.Errorf("error marshaling request: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/embeddings", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/embeddings", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, 0, fmt.Errorf("error creating request: %v", err)
+		return nil, models.TokenUsage{}, fmt.Errorf("error creating request: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -71,109 +73,48 @@ func (s *OpenAIService) GenerateEmbedding(ctx context.Context, text string) ([]f
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error calling OpenAI API: %v", err)
+		return nil, models.TokenUsage{}, fmt.Errorf("error calling OpenAI API: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusTooManyRequests {
-			return nil, 0, fmt.Errorf("cuota de OpenAI excedida. Verifica tu plan en https://platform.openai.com/account/billing")
+			return nil, models.TokenUsage{}, fmt.Errorf("%w: verifica tu plan de OpenAI en https://platform.openai.com/account/billing", ErrQuotaExceeded)
 		}
-		return nil, 0, fmt.Errorf("OpenAI API retornó status %d: %s", resp.StatusCode, string(body))
+		return nil, models.TokenUsage{}, fmt.Errorf("OpenAI API retornó status %d: %s", resp.StatusCode, string(body))
 	}
 
 	var embResp models.OpenAIEmbeddingResponse
 	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
-		return nil, 0, fmt.Errorf("error decodificando respuesta: %v", err)
-	}
-
-	if len(embResp.Data) == 0 {
-		return nil, 0, fmt.Errorf("no se recibieron embeddings")
-	}
-
-	tokens := embResp.Usage.TotalTokens
-	costo := float64(tokens) * s.PricePer1K / 1000.0
-	log.Info(ctx, "Embedding generated",
-		log.Any("tokens", tokens),
-		log.Float("costo", costo),
-	)
-
-	return embResp.Data[0].Embedding, tokens, nil
-}
-
-// GenerateAnswer genera una respuesta usando el modelo de chat de OpenAI
-func (s *OpenAIService) GenerateAnswer(ctx context.Context, query string, contextTexts []string) (string, int, error) {
-	searchContext := ""
-	for i, text := range contextTexts {
-		searchContext += fmt.Sprintf("Fragmento %d: %s\n\n", i+1, text)
-	}
-
-	systemPrompt := `Eres un asistente que responde preguntas basándote únicamente en el contexto proporcionado. 
-Responde de manera clara y concisa. Si la información no está disponible en el contexto, 
-indica que no tienes suficiente información para responder la pregunta.
-
-Contexto:
-` + searchContext
-
-	messages := []models.Message{
-		{
-			Role:    "system",
-			Content: systemPrompt,
-		},
-		{
-			Role:    "user",
-			Content: query,
-		},
-	}
-
-	reqBody := models.OpenAIChatRequest{
-		Model:       s.ChatModel,
-		Messages:    messages,
-		MaxTokens:   1000,
-		Temperature: 0.7,
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", 0, fmt.Errorf("error marshaling request: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", 0, fmt.Errorf("error creating request: %v", err)
+		return nil, models.TokenUsage{}, fmt.Errorf("error decodificando respuesta: %v", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+s.APIKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", 0, fmt.Errorf("error calling OpenAI API: %v", err)
+	if len(embResp.Data) != len(texts) {
+		return nil, models.TokenUsage{}, fmt.Errorf("se esperaban %d embeddings y se recibieron %d", len(texts), len(embResp.Data))
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode == http.StatusTooManyRequests {
-			return "", 0, fmt.Errorf("cuota de OpenAI excedida")
+	embeddings := make([][]float32, len(texts))
+	for _, data := range embResp.Data {
+		if data.Index < 0 || data.Index >= len(texts) {
+			return nil, models.TokenUsage{}, fmt.Errorf("índice de embedding inválido: %d", data.Index)
 		}
-		return "", 0, fmt.Errorf("OpenAI API retornó status %d: %s", resp.StatusCode, string(body))
+		embeddings[data.Index] = data.Embedding
 	}
 
-	var chatResp models.OpenAIChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return "", 0, fmt.Errorf("error decodificando respuesta: %v", err)
+	usage := models.TokenUsage{
+		Model:        s.Model,
+		PromptTokens: embResp.Usage.PromptTokens,
+		TotalTokens:  embResp.Usage.TotalTokens,
 	}
-
-	if len(chatResp.Choices) == 0 {
-		return "", 0, fmt.Errorf("no se recibió respuesta de OpenAI")
+	if usage.CostUSD, err = s.Pricing.Cost(usage); err != nil {
+		return nil, models.TokenUsage{}, err
 	}
+	log.Info(ctx, "Embedding generated",
+		log.Int("inputs", len(texts)),
+		log.Any("tokens", usage.TotalTokens),
+		log.Float("costo", usage.CostUSD),
+	)
 
-	tokens := chatResp.Usage.TotalTokens
-	costo := float64(tokens) * s.ChatPricePer1K / 1000.0
-	log.Info(ctx, "Got answer", log.Any("tokens", tokens), log.Float("costo", costo))
-
-	return chatResp.Choices[0].Message.Content, tokens, nil
+	return embeddings, usage, nil
 }
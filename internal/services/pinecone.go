@@ -65,7 +65,7 @@ func NewPineconeService(apiKey, indexName string) (*PineconeService, error) {
 	log.Info(context.Background(), "Conectado a Pinecone", log.Any("index", index), log.Any("stats", stats))
 
 	// Cargar videos desde el archivo JSON
-	videos, err := loadVideos()
+	videos, err := LoadVideos()
 	if err != nil {
 		log.Error(context.Background(), "Error cargando videos.json", log.Err(err))
 		videos = make(map[string]models.Video) // Continuar con mapa vacío
@@ -79,8 +79,8 @@ func NewPineconeService(apiKey, indexName string) (*PineconeService, error) {
 	}, nil
 }
 
-// loadVideos carga los videos desde el archivo videos.json
-func loadVideos() (map[string]models.Video, error) {
+// LoadVideos carga los videos desde el archivo videos.json, indexados por ID
+func LoadVideos() (map[string]models.Video, error) {
 	data, err := os.ReadFile("videos.json")
 	if err != nil {
 		return nil, fmt.Errorf("error leyendo videos.json: %v", err)
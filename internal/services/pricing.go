@@ -0,0 +1,38 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// Pricing es la tabla de precios por nombre de modelo
+type Pricing map[string]models.ModelPrice
+
+// Has indica si el modelo tiene precio configurado
+func (p Pricing) Has(model string) bool {
+	_, ok := p[model]
+	return ok
+}
+
+// Cost calcula el costo en USD de un consumo, separando tokens de entrada,
+// de entrada cacheados y de salida. Si el modelo no define precio para
+// entrada cacheada se usa el de entrada.
+func (p Pricing) Cost(usage models.TokenUsage) (float64, error) {
+	price, ok := p[usage.Model]
+	if !ok {
+		return 0, fmt.Errorf("modelo sin precio configurado: %s", usage.Model)
+	}
+
+	cachedPrice := price.CachedInputPer1K
+	if cachedPrice == 0 {
+		cachedPrice = price.InputPer1K
+	}
+
+	uncached := usage.PromptTokens - usage.CachedTokens
+	cost := float64(uncached)*price.InputPer1K +
+		float64(usage.CachedTokens)*cachedPrice +
+		float64(usage.CompletionTokens)*price.OutputPer1K
+
+	return cost / 1000.0, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// promptExt es la extensión de los archivos de templates de prompt
+const promptExt = ".tmpl"
+
+// PromptFragment es un fragmento de contexto disponible para los templates
+type PromptFragment struct {
+	Index      int    // posición 1-based dentro del contexto
+	Header     string // título del video y timestamp
+	Text       string
+	Video      string
+	VideoTitle string
+	StartSec   float64
+	Timestamp  string // StartSec formateado como h:mm:ss o m:ss
+}
+
+// PromptData contiene las variables disponibles para los templates de prompt
+type PromptData struct {
+	Query     string
+	Style     string
+	Language  string // código del idioma de la respuesta (es, en)
+	Fragments []PromptFragment
+	Videos    []string // títulos de los videos del contexto, sin repetir
+}
+
+// PromptStore carga templates de prompt versionados desde un directorio.
+// Cada archivo <version>.tmpl es una versión; Reload los vuelve a leer en caliente.
+type PromptStore struct {
+	mu        sync.RWMutex
+	dir       string
+	required  []string // versiones configuradas que deben existir siempre
+	templates map[string]*template.Template
+}
+
+// NewPromptStore carga los templates del directorio indicado. required son las
+// versiones configuradas (la default y las asignadas a API keys): si falta alguna
+// la carga falla.
+func NewPromptStore(dir string, required []string) (*PromptStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("prompts dir is required")
+	}
+
+	store := &PromptStore{dir: dir, required: required}
+	if _, err := store.Reload(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Reload vuelve a leer los templates. Si alguno es inválido o falta una versión
+// requerida se conservan los anteriores.
+func (s *PromptStore) Reload() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*"+promptExt))
+	if err != nil {
+		return nil, fmt.Errorf("error listando templates de prompt: %v", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no hay templates de prompt en %s", s.dir)
+	}
+
+	templates := make(map[string]*template.Template, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo template %s: %v", path, err)
+		}
+
+		version := strings.TrimSuffix(filepath.Base(path), promptExt)
+		tmpl, err := template.New(version).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("template de prompt inválido %s: %v", path, err)
+		}
+		templates[version] = tmpl
+	}
+	for _, version := range s.required {
+		if _, ok := templates[version]; !ok {
+			return nil, fmt.Errorf("prompt version %s not found in %s", version, s.dir)
+		}
+	}
+
+	s.mu.Lock()
+	s.templates = templates
+	s.mu.Unlock()
+
+	return s.Versions(), nil
+}
+
+// Has indica si existe la versión de template
+func (s *PromptStore) Has(version string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.templates[version]
+	return ok
+}
+
+// Versions retorna las versiones cargadas, ordenadas
+func (s *PromptStore) Versions() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := make([]string, 0, len(s.templates))
+	for version := range s.templates {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Render ejecuta la versión de template indicada con los datos del prompt
+func (s *PromptStore) Render(version string, data PromptData) (string, error) {
+	s.mu.RLock()
+	tmpl, ok := s.templates[version]
+	s.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("versión de prompt inexistente: %s", version)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error ejecutando template %s: %v", version, err)
+	}
+	return buf.String(), nil
+}
+
+// FormatTimestamp formatea segundos como h:mm:ss, o m:ss si dura menos de una hora
+func FormatTimestamp(seconds float64) string {
+	total := int(seconds)
+	h, m, sec := total/3600, (total%3600)/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%d:%02d", m, sec)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// charsPerToken es la cantidad aproximada de caracteres por token de los modelos de OpenAI
+const charsPerToken = 4
+
+// EstimateTokens estima los tokens de un texto sin tokenizarlo. Toma el máximo entre
+// caracteres/4 y la cantidad de palabras, lo que sobreestima levemente para texto en
+// español y nunca cuenta menos de un token por palabra.
+func EstimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+
+	byChars := (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+	byWords := len(strings.Fields(text))
+	if byWords > byChars {
+		return byWords
+	}
+	return byChars
+}
+
+// TruncateToTokens recorta el texto para que su estimación no supere maxTokens,
+// cortando en el último espacio para no partir palabras
+func TruncateToTokens(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+
+	for EstimateTokens(text) > maxTokens {
+		runes := []rune(text)
+		limit := len(runes) * maxTokens / EstimateTokens(text)
+		if limit >= len(runes) {
+			limit = len(runes) - 1
+		}
+		cut := string(runes[:limit])
+		if i := strings.LastIndexByte(cut, ' '); i > 0 {
+			cut = cut[:i]
+		}
+		text = strings.TrimSpace(cut)
+	}
+	return text
+}
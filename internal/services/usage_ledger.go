@@ -0,0 +1,99 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// UsageLedger persiste el consumo de tokens y costos en un archivo JSON Lines
+type UsageLedger struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// NewUsageLedger abre (o crea) el archivo del ledger en modo append
+func NewUsageLedger(path string) (*UsageLedger, error) {
+	if path == "" {
+		return nil, fmt.Errorf("ledger path is required")
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("error creando directorio del ledger: %v", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo ledger: %v", err)
+	}
+
+	return &UsageLedger{
+		path: path,
+		file: file,
+	}, nil
+}
+
+// Append agrega un registro al ledger y lo sincroniza a disco
+func (l *UsageLedger) Append(entry models.UsageEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error serializando registro de uso: %v", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(line); err != nil {
+		return fmt.Errorf("error escribiendo ledger: %v", err)
+	}
+	return l.file.Sync()
+}
+
+// Entries retorna los registros con timestamp en [from, to)
+func (l *UsageLedger) Entries(from, to time.Time) ([]models.UsageEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo ledger: %v", err)
+	}
+	defer file.Close()
+
+	var entries []models.UsageEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry models.UsageEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Una línea truncada (por ejemplo tras un corte) no invalida el resto
+			continue
+		}
+		if entry.Timestamp.Before(from) || !entry.Timestamp.Before(to) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo ledger: %v", err)
+	}
+
+	return entries, nil
+}
+
+// Close cierra el archivo del ledger
+func (l *UsageLedger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
@@ -0,0 +1,67 @@
+package subtitles
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Paragraph es un tramo de transcripción armado con cues consecutivos
+type Paragraph struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// Paragraphs une los cues en párrafos. Un párrafo termina ante una pausa de al menos
+// pause entre cues, o en un fin de oración una vez que dura length. Las transcripciones
+// sin puntuación se cortan igual al llegar a 2*length.
+func Paragraphs(cues []Cue, pause, length time.Duration) []Paragraph {
+	var paragraphs []Paragraph
+	var current *Paragraph
+	var words []string
+
+	flush := func() {
+		if current != nil && len(words) > 0 {
+			current.Text = strings.Join(words, " ")
+			paragraphs = append(paragraphs, *current)
+		}
+		current, words = nil, nil
+	}
+
+	for _, cue := range cues {
+		fields := strings.Fields(cue.Text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if current != nil {
+			elapsed := current.End - current.Start
+			switch {
+			case cue.Start-current.End >= pause:
+				flush()
+			case elapsed >= length && endsSentence(words[len(words)-1]):
+				flush()
+			case elapsed >= 2*length:
+				flush()
+			}
+		}
+
+		if current == nil {
+			current = &Paragraph{Start: cue.Start}
+		}
+		current.End = max(current.End, cue.End)
+		words = append(words, fields...)
+	}
+	flush()
+
+	return paragraphs
+}
+
+// endsSentence indica si la palabra cierra una oración, ignorando comillas y
+// paréntesis de cierre
+func endsSentence(word string) bool {
+	word = strings.TrimRight(word, "\"'»”)]")
+	last, _ := utf8.DecodeLastRuneInString(word)
+	return strings.ContainsRune(".?!…", last)
+}
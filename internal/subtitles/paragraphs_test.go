@@ -0,0 +1,121 @@
+package subtitles
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParagraphs(t *testing.T) {
+	sec := func(s float64) time.Duration { return FromSeconds(s) }
+	cue := func(start, end float64, text string) Cue {
+		return Cue{Start: sec(start), End: sec(end), Text: text}
+	}
+
+	tests := []struct {
+		name   string
+		cues   []Cue
+		pause  time.Duration
+		length time.Duration
+		want   []Paragraph
+	}{
+		{
+			name:   "sin cues",
+			pause:  2 * time.Second,
+			length: 30 * time.Second,
+			want:   nil,
+		},
+		{
+			name:   "une cues consecutivos",
+			cues:   []Cue{cue(0, 2, "Hola"), cue(2, 4, "a todos.")},
+			pause:  2 * time.Second,
+			length: 30 * time.Second,
+			want:   []Paragraph{{Start: 0, End: sec(4), Text: "Hola a todos."}},
+		},
+		{
+			name:   "corta en una pausa",
+			cues:   []Cue{cue(0, 2, "Uno"), cue(4, 6, "dos")},
+			pause:  2 * time.Second,
+			length: 30 * time.Second,
+			want: []Paragraph{
+				{Start: 0, End: sec(2), Text: "Uno"},
+				{Start: sec(4), End: sec(6), Text: "dos"},
+			},
+		},
+		{
+			name:   "corta en fin de oración al llegar a length",
+			cues:   []Cue{cue(0, 5, "Primera oración."), cue(5, 10, "Sigue «entre comillas.»"), cue(10, 12, "Otra")},
+			pause:  2 * time.Second,
+			length: 8 * time.Second,
+			want: []Paragraph{
+				{Start: 0, End: sec(10), Text: "Primera oración. Sigue «entre comillas.»"},
+				{Start: sec(10), End: sec(12), Text: "Otra"},
+			},
+		},
+		{
+			name:   "no corta antes de length",
+			cues:   []Cue{cue(0, 3, "Corta."), cue(3, 6, "Sigue.")},
+			pause:  2 * time.Second,
+			length: 8 * time.Second,
+			want:   []Paragraph{{Start: 0, End: sec(6), Text: "Corta. Sigue."}},
+		},
+		{
+			name:   "sin puntuación corta en 2*length",
+			cues:   []Cue{cue(0, 5, "uno"), cue(5, 10, "dos"), cue(10, 15, "tres"), cue(15, 20, "cuatro")},
+			pause:  2 * time.Second,
+			length: 5 * time.Second,
+			want: []Paragraph{
+				{Start: 0, End: sec(10), Text: "uno dos"},
+				{Start: sec(10), End: sec(20), Text: "tres cuatro"},
+			},
+		},
+		{
+			name:   "ignora cues vacíos y normaliza espacios",
+			cues:   []Cue{cue(0, 1, "Hola\n  mundo"), cue(1, 2, " "), cue(2, 3, "otra vez")},
+			pause:  2 * time.Second,
+			length: 30 * time.Second,
+			want:   []Paragraph{{Start: 0, End: sec(3), Text: "Hola mundo otra vez"}},
+		},
+		{
+			name:   "cues superpuestos conservan el mayor fin",
+			cues:   []Cue{cue(0, 5, "largo"), cue(1, 3, "corto")},
+			pause:  2 * time.Second,
+			length: 30 * time.Second,
+			want:   []Paragraph{{Start: 0, End: sec(5), Text: "largo corto"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Paragraphs(tt.cues, tt.pause, tt.length)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Paragraphs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndsSentence(t *testing.T) {
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{word: "fin.", want: true},
+		{word: "¿qué?", want: true},
+		{word: "¡ya!", want: true},
+		{word: "bueno…", want: true},
+		{word: "dijo.\"", want: true},
+		{word: "(esto.)", want: true},
+		{word: "coma,", want: false},
+		{word: "palabra", want: false},
+		{word: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := endsSentence(tt.word); got != tt.want {
+				t.Errorf("endsSentence(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
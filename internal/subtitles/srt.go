@@ -0,0 +1,43 @@
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// ParseSRT parsea subtítulos en formato SubRip. El número de cada bloque se conserva
+// como ID del cue.
+func ParseSRT(r io.Reader) ([]Cue, error) {
+	blocks, err := readBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cues []Cue
+	for _, block := range blocks {
+		cue, ok, err := parseCueBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			cues = append(cues, cue)
+		}
+	}
+
+	return cues, nil
+}
+
+// WriteSRT escribe los cues en formato SubRip, numerados desde 1
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+
+	for i, cue := range cues {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n", i+1, FormatTimestamp(cue.Start, ','), FormatTimestamp(cue.End, ','), cue.Text)
+	}
+
+	return bw.Flush()
+}
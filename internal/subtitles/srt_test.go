@@ -0,0 +1,84 @@
+package subtitles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSRT(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Cue
+		wantErr bool
+	}{
+		{
+			name:  "básico con coma",
+			input: "1\n00:00:01,000 --> 00:00:02,500\nHola\n\n2\n00:00:03,000 --> 00:00:04,000\nmundo\n",
+			want: []Cue{
+				{ID: "1", Start: time.Second, End: 2500 * time.Millisecond, Text: "Hola"},
+				{ID: "2", Start: 3 * time.Second, End: 4 * time.Second, Text: "mundo"},
+			},
+		},
+		{
+			name:  "milisegundos con punto",
+			input: "1\n00:00:01.250 --> 00:00:02.000\nHola\n",
+			want:  []Cue{{ID: "1", Start: 1250 * time.Millisecond, End: 2 * time.Second, Text: "Hola"}},
+		},
+		{
+			name:  "con BOM y CRLF",
+			input: "\uFEFF1\r\n00:00:01,000 --> 00:00:02,000\r\nHola\r\n",
+			want:  []Cue{{ID: "1", Start: time.Second, End: 2 * time.Second, Text: "Hola"}},
+		},
+		{
+			name:  "sin número de bloque",
+			input: "00:00:01,000 --> 00:00:02,000\nHola\n",
+			want:  []Cue{{Start: time.Second, End: 2 * time.Second, Text: "Hola"}},
+		},
+		{
+			name:  "quita etiquetas y overrides de estilo",
+			input: "1\n00:00:01,000 --> 00:00:02,000\n{\\an8}<b>Hola</b>\nmundo\n",
+			want:  []Cue{{ID: "1", Start: time.Second, End: 2 * time.Second, Text: "Hola\nmundo"}},
+		},
+		{
+			name:  "vacío",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:    "timestamp inválido",
+			input:   "1\n00:00:01,000 --> xx\nHola\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSRT(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSRT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSRT() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteSRT(t *testing.T) {
+	cues := []Cue{
+		{ID: "intro", Start: time.Second, End: 2500 * time.Millisecond, Text: "Hola"},
+		{Start: time.Hour, End: time.Hour + time.Second, Text: "mundo"},
+	}
+	want := "1\n00:00:01,000 --> 00:00:02,500\nHola\n\n2\n01:00:00,000 --> 01:00:01,000\nmundo\n"
+
+	var b strings.Builder
+	if err := WriteSRT(&b, cues); err != nil {
+		t.Fatalf("WriteSRT() error = %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("WriteSRT() = %q, want %q", b.String(), want)
+	}
+}
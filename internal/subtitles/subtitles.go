@@ -0,0 +1,212 @@
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/pkg/utils"
+)
+
+// Formatos de subtítulos. VTT y SRT se pueden leer y escribir; texto y JSON solo escribir.
+const (
+	FormatVTT  = "vtt"
+	FormatSRT  = "srt"
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// SourceFormats son los formatos que se pueden leer, en orden de preferencia
+var SourceFormats = []string{FormatVTT, FormatSRT}
+
+// Cue es un bloque de subtítulo con su intervalo de tiempo
+type Cue struct {
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	Text  string // puede tener varias líneas, sin etiquetas de formato
+}
+
+// tagPattern reconoce etiquetas de formato de WebVTT y SRT (<c>, <v Nombre>, <i>,
+// <00:00:01.000>) y overrides de estilo de SRT ({\an8})
+var tagPattern = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+
+// FormatOf retorna el formato de un archivo según su extensión, o vacío si no se puede leer
+func FormatOf(path string) string {
+	format := strings.TrimPrefix(utils.GetFileExtension(path), ".")
+	if !utils.ContainsString(SourceFormats, format) {
+		return ""
+	}
+	return format
+}
+
+// ReadFile parsea un archivo de subtítulos en cualquiera de los SourceFormats
+func ReadFile(path string) ([]Cue, error) {
+	format := FormatOf(path)
+	if format == "" {
+		return nil, fmt.Errorf("formato de subtítulos no soportado: %s", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file, format)
+}
+
+// Parse parsea subtítulos en el formato indicado
+func Parse(r io.Reader, format string) ([]Cue, error) {
+	switch format {
+	case FormatVTT:
+		return ParseVTT(r)
+	case FormatSRT:
+		return ParseSRT(r)
+	default:
+		return nil, fmt.Errorf("formato de subtítulos no soportado: %s", format)
+	}
+}
+
+// Write escribe los cues en el formato indicado
+func Write(w io.Writer, cues []Cue, format string) error {
+	switch format {
+	case FormatVTT:
+		return WriteVTT(w, cues)
+	case FormatSRT:
+		return WriteSRT(w, cues)
+	case FormatText:
+		return WriteText(w, cues)
+	case FormatJSON:
+		return WriteJSON(w, cues)
+	default:
+		return fmt.Errorf("formato de subtítulos no soportado: %s", format)
+	}
+}
+
+// readBlocks separa el archivo en bloques de líneas no vacías
+func readBlocks(r io.Reader) ([][]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var blocks [][]string
+	var block []string
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if first {
+			line = strings.TrimPrefix(line, "\uFEFF")
+			first = false
+		}
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = nil
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo subtítulos: %v", err)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// parseCueBlock parsea un bloque con identificador opcional, línea de tiempo y texto.
+// Retorna false si el bloque no tiene línea de tiempo.
+func parseCueBlock(block []string) (Cue, bool, error) {
+	cue := Cue{}
+	timing := block[0]
+	text := block[1:]
+	if !strings.Contains(timing, "-->") {
+		if len(block) < 2 {
+			return cue, false, nil
+		}
+		cue.ID = block[0]
+		timing = block[1]
+		text = block[2:]
+	}
+
+	start, end, err := parseTiming(timing)
+	if err != nil {
+		return cue, false, err
+	}
+	cue.Start, cue.End = start, end
+	cue.Text = strings.TrimSpace(tagPattern.ReplaceAllString(strings.Join(text, "\n"), ""))
+	return cue, true, nil
+}
+
+// parseTiming parsea "00:00:01.000 --> 00:00:04.000 align:start" (o con coma en SRT)
+func parseTiming(line string) (time.Duration, time.Duration, error) {
+	startText, rest, ok := strings.Cut(line, "-->")
+	if !ok {
+		return 0, 0, fmt.Errorf("línea de tiempo inválida: %q", line)
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("línea de tiempo inválida: %q", line)
+	}
+
+	start, err := ParseTimestamp(strings.TrimSpace(startText))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := ParseTimestamp(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// ParseTimestamp parsea "hh:mm:ss.ttt" o "mm:ss.ttt". También acepta coma como
+// separador de milisegundos (formato SRT).
+func ParseTimestamp(value string) (time.Duration, error) {
+	value = strings.Replace(value, ",", ".", 1)
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("timestamp inválido: %q", value)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("timestamp inválido: %q", value)
+	}
+	minutes, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil {
+		return 0, fmt.Errorf("timestamp inválido: %q", value)
+	}
+	hours := 0
+	if len(parts) == 3 {
+		if hours, err = strconv.Atoi(parts[0]); err != nil {
+			return 0, fmt.Errorf("timestamp inválido: %q", value)
+		}
+	}
+
+	total := float64(hours*3600+minutes*60) + seconds
+	return time.Duration(total * float64(time.Second)).Round(time.Millisecond), nil
+}
+
+// FromSeconds convierte segundos a time.Duration redondeando a milisegundos
+func FromSeconds(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
+
+// FormatTimestamp formatea d como "hh:mm:ss.ttt", con sep como separador de
+// milisegundos ('.' en WebVTT, ',' en SRT)
+func FormatTimestamp(d time.Duration, sep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
@@ -0,0 +1,60 @@
+package subtitles
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "con horas y punto", value: "01:02:03.456", want: time.Hour + 2*time.Minute + 3456*time.Millisecond},
+		{name: "con horas y coma", value: "01:02:03,456", want: time.Hour + 2*time.Minute + 3456*time.Millisecond},
+		{name: "sin horas", value: "02:03.456", want: 2*time.Minute + 3456*time.Millisecond},
+		{name: "sin horas con coma", value: "00:01,500", want: 1500 * time.Millisecond},
+		{name: "sin milisegundos", value: "00:00:05", want: 5 * time.Second},
+		{name: "más de 99 horas", value: "100:00:00.000", want: 100 * time.Hour},
+		{name: "solo segundos", value: "05.000", wantErr: true},
+		{name: "demasiados campos", value: "00:00:00:01.000", wantErr: true},
+		{name: "minutos no numéricos", value: "00:xx:01.000", wantErr: true},
+		{name: "vacío", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimestamp(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseTimestamp(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		sep  byte
+		want string
+	}{
+		{name: "webvtt", d: time.Hour + 2*time.Minute + 3456*time.Millisecond, sep: '.', want: "01:02:03.456"},
+		{name: "srt", d: 1500 * time.Millisecond, sep: ',', want: "00:00:01,500"},
+		{name: "cero", d: 0, sep: '.', want: "00:00:00.000"},
+		{name: "negativo", d: -time.Second, sep: '.', want: "00:00:00.000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTimestamp(tt.d, tt.sep); got != tt.want {
+				t.Errorf("FormatTimestamp(%v, %q) = %q, want %q", tt.d, tt.sep, got, tt.want)
+			}
+		})
+	}
+}
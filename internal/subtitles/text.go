@@ -0,0 +1,48 @@
+package subtitles
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// jsonCue es la representación JSON de un cue, con tiempos en segundos y en formato WebVTT
+type jsonCue struct {
+	ID       string  `json:"id,omitempty"`
+	StartSec float64 `json:"start_sec"`
+	EndSec   float64 `json:"end_sec"`
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+	Text     string  `json:"text"`
+}
+
+// WriteText escribe solo el texto de los cues, uno por línea
+func WriteText(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for _, cue := range cues {
+		bw.WriteString(strings.Join(strings.Fields(cue.Text), " "))
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// WriteJSON escribe los cues como {"cues": [...]}
+func WriteJSON(w io.Writer, cues []Cue) error {
+	out := struct {
+		Cues []jsonCue `json:"cues"`
+	}{Cues: make([]jsonCue, len(cues))}
+
+	for i, cue := range cues {
+		out.Cues[i] = jsonCue{
+			ID:       cue.ID,
+			StartSec: cue.Start.Seconds(),
+			EndSec:   cue.End.Seconds(),
+			Start:    FormatTimestamp(cue.Start, '.'),
+			End:      FormatTimestamp(cue.End, '.'),
+			Text:     cue.Text,
+		}
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
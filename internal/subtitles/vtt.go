@@ -0,0 +1,88 @@
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseVTT parsea subtítulos en formato WebVTT. Ignora el encabezado, los bloques
+// NOTE, STYLE y REGION, y los ajustes de posición de cada cue, y decodifica las
+// entidades del texto.
+func ParseVTT(r io.Reader) ([]Cue, error) {
+	blocks, err := readBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(blocks) == 0 || !strings.HasPrefix(blocks[0][0], "WEBVTT") {
+		return nil, fmt.Errorf("archivo WebVTT inválido: falta el encabezado WEBVTT")
+	}
+
+	var cues []Cue
+	for _, block := range blocks[1:] {
+		if isMetadataBlock(block[0]) {
+			continue
+		}
+
+		cue, ok, err := parseCueBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			cue.Text = vttUnescaper.Replace(cue.Text)
+			cues = append(cues, cue)
+		}
+	}
+
+	return cues, nil
+}
+
+func isMetadataBlock(line string) bool {
+	return strings.HasPrefix(line, "NOTE") || strings.HasPrefix(line, "STYLE") || strings.HasPrefix(line, "REGION")
+}
+
+// vttEscaper escapa el texto de los cues: "&" y "<" abrirían entidades o etiquetas,
+// y escapar ">" evita que el texto contenga "-->"
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// vttUnescaper decodifica las entidades que admite WebVTT
+var vttUnescaper = strings.NewReplacer(
+	"&amp;", "&", "&lt;", "<", "&gt;", ">",
+	"&nbsp;", "\u00a0", "&lrm;", "\u200e", "&rlm;", "\u200f",
+)
+
+// WriteVTT escribe los cues en formato WebVTT. El texto se escapa y se le quitan las
+// líneas vacías, que cortarían el cue; puede venir del modelo de chat o de títulos.
+func WriteVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n")
+
+	for _, cue := range cues {
+		bw.WriteString("\n")
+		if id := vttCueID(cue.ID); id != "" {
+			fmt.Fprintf(bw, "%s\n", id)
+		}
+		fmt.Fprintf(bw, "%s --> %s\n%s\n", FormatTimestamp(cue.Start, '.'), FormatTimestamp(cue.End, '.'), vttCueText(cue.Text))
+	}
+
+	return bw.Flush()
+}
+
+// vttCueText escapa cada línea del texto y descarta las vacías
+func vttCueText(text string) string {
+	var lines []string
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, vttEscaper.Replace(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// vttCueID deja el identificador en una línea y sin "-->", que lo confundiría con la
+// línea de tiempo
+func vttCueID(id string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(id), " "), "-->", "->")
+}
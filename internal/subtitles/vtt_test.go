@@ -0,0 +1,146 @@
+package subtitles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseVTT(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Cue
+		wantErr bool
+	}{
+		{
+			name:  "básico",
+			input: "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nHola\n\n00:00:03.000 --> 00:00:04.000\nmundo\n",
+			want: []Cue{
+				{Start: time.Second, End: 2500 * time.Millisecond, Text: "Hola"},
+				{Start: 3 * time.Second, End: 4 * time.Second, Text: "mundo"},
+			},
+		},
+		{
+			name:  "con BOM y CRLF",
+			input: "\uFEFFWEBVTT\r\n\r\n00:00:01.000 --> 00:00:02.000\r\nHola\r\n",
+			want:  []Cue{{Start: time.Second, End: 2 * time.Second, Text: "Hola"}},
+		},
+		{
+			name:  "timestamps sin horas",
+			input: "WEBVTT\n\n01:02.000 --> 01:03.500\nHola\n",
+			want:  []Cue{{Start: 62 * time.Second, End: 63500 * time.Millisecond, Text: "Hola"}},
+		},
+		{
+			name:  "id de cue y ajustes de posición",
+			input: "WEBVTT\n\nintro\n00:00:01.000 --> 00:00:02.000 align:start position:10%\nHola\n",
+			want:  []Cue{{ID: "intro", Start: time.Second, End: 2 * time.Second, Text: "Hola"}},
+		},
+		{
+			name:  "ignora NOTE, STYLE y REGION",
+			input: "WEBVTT - título\n\nNOTE comentario\n\nSTYLE\n::cue { color: red }\n\nREGION\nid:r1\n\n00:00:01.000 --> 00:00:02.000\nHola\n",
+			want:  []Cue{{Start: time.Second, End: 2 * time.Second, Text: "Hola"}},
+		},
+		{
+			name:  "quita etiquetas y conserva líneas",
+			input: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n<v Ana>Hola</v>\n<i>mundo</i>\n",
+			want:  []Cue{{Start: time.Second, End: 2 * time.Second, Text: "Hola\nmundo"}},
+		},
+		{
+			name:  "decodifica entidades",
+			input: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nQ&amp;A: 1 &lt; 2 &amp;lt;b&amp;gt;\n",
+			want:  []Cue{{Start: time.Second, End: 2 * time.Second, Text: "Q&A: 1 < 2 &lt;b&gt;"}},
+		},
+		{
+			name:  "bloque sin línea de tiempo",
+			input: "WEBVTT\n\nsuelto\n\n00:00:01.000 --> 00:00:02.000\nHola\n",
+			want:  []Cue{{Start: time.Second, End: 2 * time.Second, Text: "Hola"}},
+		},
+		{
+			name:    "sin encabezado",
+			input:   "00:00:01.000 --> 00:00:02.000\nHola\n",
+			wantErr: true,
+		},
+		{
+			name:    "timestamp inválido",
+			input:   "WEBVTT\n\n00:00:xx.000 --> 00:00:02.000\nHola\n",
+			wantErr: true,
+		},
+		{
+			name:    "vacío",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVTT(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVTT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseVTT() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteVTT(t *testing.T) {
+	cues := []Cue{
+		{ID: "1", Start: time.Second, End: 2500 * time.Millisecond, Text: "Hola"},
+		{Start: 3 * time.Second, End: 4 * time.Second, Text: "dos\nlíneas"},
+	}
+	want := "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.500\nHola\n\n00:00:03.000 --> 00:00:04.000\ndos\nlíneas\n"
+
+	var b strings.Builder
+	if err := WriteVTT(&b, cues); err != nil {
+		t.Fatalf("WriteVTT() error = %v", err)
+	}
+	if b.String() != want {
+		t.Fatalf("WriteVTT() = %q, want %q", b.String(), want)
+	}
+
+	parsed, err := ParseVTT(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("ParseVTT() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, cues) {
+		t.Errorf("ParseVTT(WriteVTT()) = %+v, want %+v", parsed, cues)
+	}
+}
+
+func TestWriteVTTEscapesText(t *testing.T) {
+	cues := []Cue{
+		{ID: "a --> b", Start: time.Second, End: 2 * time.Second, Text: "Q&A: 1 < 2 --> <b>fin</b>"},
+		{ID: "dos\nlíneas", Start: 3 * time.Second, End: 4 * time.Second, Text: "antes\n\n  \r\ndespués\n"},
+		{Start: 5 * time.Second, End: 6 * time.Second, Text: "&amp; literal"},
+	}
+	want := "WEBVTT\n\n" +
+		"a -> b\n00:00:01.000 --> 00:00:02.000\nQ&amp;A: 1 &lt; 2 --&gt; &lt;b&gt;fin&lt;/b&gt;\n\n" +
+		"dos líneas\n00:00:03.000 --> 00:00:04.000\nantes\ndespués\n\n" +
+		"00:00:05.000 --> 00:00:06.000\n&amp;amp; literal\n"
+
+	var b strings.Builder
+	if err := WriteVTT(&b, cues); err != nil {
+		t.Fatalf("WriteVTT() error = %v", err)
+	}
+	if b.String() != want {
+		t.Fatalf("WriteVTT() = %q, want %q", b.String(), want)
+	}
+
+	parsed, err := ParseVTT(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("ParseVTT() error = %v", err)
+	}
+	if len(parsed) != len(cues) {
+		t.Fatalf("ParseVTT(WriteVTT()) = %d cues, want %d", len(parsed), len(cues))
+	}
+	wantText := []string{"Q&A: 1 < 2 --> <b>fin</b>", "antes\ndespués", "&amp; literal"}
+	for i, cue := range parsed {
+		if cue.Text != wantText[i] {
+			t.Errorf("cue %d: texto = %q, want %q", i, cue.Text, wantText[i])
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package subtitles
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Wrap reparte el texto de un cue en líneas de hasta maxLineLength caracteres, cortando
+// entre palabras. Si no entra en maxLines líneas se usan maxLines líneas de largo
+// parejo, aunque superen el límite: nunca se descarta texto.
+func Wrap(text string, maxLineLength, maxLines int) string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return ""
+	}
+
+	lines := wrapWords(words, maxLineLength)
+	if len(lines) <= maxLines {
+		return strings.Join(lines, "\n")
+	}
+
+	// Buscar el menor largo de línea con el que el texto entra en maxLines líneas
+	total := utf8.RuneCountInString(strings.Join(words, " "))
+	for width := maxLineLength + 1; width < total; width++ {
+		if lines = wrapWords(words, width); len(lines) <= maxLines {
+			return strings.Join(lines, "\n")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// wrapWords arma líneas de hasta width caracteres; una palabra más larga queda sola
+func wrapWords(words []string, width int) []string {
+	var lines []string
+	line := words[0]
+	for _, word := range words[1:] {
+		if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line += " " + word
+	}
+	return append(lines, line)
+}
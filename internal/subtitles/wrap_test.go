@@ -0,0 +1,31 @@
+package subtitles
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		maxLineLength int
+		maxLines      int
+		want          string
+	}{
+		{name: "vacío", text: "  \n ", maxLineLength: 42, maxLines: 2, want: ""},
+		{name: "entra en una línea", text: "Hola mundo", maxLineLength: 42, maxLines: 2, want: "Hola mundo"},
+		{name: "normaliza espacios y saltos", text: "Hola\n  mundo", maxLineLength: 42, maxLines: 2, want: "Hola mundo"},
+		{name: "corta entre palabras", text: "uno dos tres cuatro", maxLineLength: 9, maxLines: 3, want: "uno dos\ntres\ncuatro"},
+		{name: "cuenta runas, no bytes", text: "ñandú águila", maxLineLength: 12, maxLines: 1, want: "ñandú águila"},
+		{name: "palabra más larga que la línea", text: "supercalifragilístico sí", maxLineLength: 10, maxLines: 2, want: "supercalifragilístico\nsí"},
+		{name: "ensancha hasta entrar en maxLines", text: "uno dos tres cuatro", maxLineLength: 9, maxLines: 2, want: "uno dos\ntres cuatro"},
+		{name: "ensancha las líneas para no pasar maxLines", text: "uno dos tres cuatro cinco", maxLineLength: 8, maxLines: 2, want: "uno dos tres\ncuatro cinco"},
+		{name: "una sola línea si no entra de otro modo", text: "uno dos tres", maxLineLength: 3, maxLines: 1, want: "uno dos tres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Wrap(tt.text, tt.maxLineLength, tt.maxLines); got != tt.want {
+				t.Errorf("Wrap(%q, %d, %d) = %q, want %q", tt.text, tt.maxLineLength, tt.maxLines, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+// BudgetLevel indica el estado del presupuesto para una solicitud
+type BudgetLevel string
+
+const (
+	BudgetOK        BudgetLevel = "ok"
+	BudgetSoftLimit BudgetLevel = "soft_limit"
+	BudgetHardLimit BudgetLevel = "hard_limit"
+)
+
+// spendWindow acumula el gasto del día y del mes en curso (UTC)
+type spendWindow struct {
+	day        string
+	dailyUSD   float64
+	month      string
+	monthlyUSD float64
+}
+
+func (w *spendWindow) roll(now time.Time) {
+	if day := now.Format("2006-01-02"); w.day != day {
+		w.day = day
+		w.dailyUSD = 0
+	}
+	if month := now.Format("2006-01"); w.month != month {
+		w.month = month
+		w.monthlyUSD = 0
+	}
+}
+
+func (w *spendWindow) level(limits config.BudgetLimits) BudgetLevel {
+	switch {
+	case exceeds(w.dailyUSD, limits.DailyHardUSD), exceeds(w.monthlyUSD, limits.MonthlyHardUSD):
+		return BudgetHardLimit
+	case exceeds(w.dailyUSD, limits.DailySoftUSD), exceeds(w.monthlyUSD, limits.MonthlySoftUSD):
+		return BudgetSoftLimit
+	default:
+		return BudgetOK
+	}
+}
+
+func exceeds(spent, limit float64) bool {
+	return limit > 0 && spent >= limit
+}
+
+// BudgetUseCaseImpl lleva el gasto en USD en memoria, global y por API key
+type BudgetUseCaseImpl struct {
+	mu      sync.Mutex
+	config  config.BudgetConfig
+	global  spendWindow
+	apiKeys map[string]*spendWindow
+	now     func() time.Time
+}
+
+// NewBudgetUseCase crea una nueva instancia del use case de presupuestos.
+// El gasto del mes en curso se reconstruye desde el ledger de uso para sobrevivir reinicios.
+func NewBudgetUseCase(config config.Config, ledger *services.UsageLedger) (BudgetUseCase, error) {
+	return newBudgetUseCase(config.Budget, ledger, func() time.Time { return time.Now().UTC() })
+}
+
+// newBudgetUseCase crea el use case con el reloj indicado
+func newBudgetUseCase(config config.BudgetConfig, ledger *services.UsageLedger, clock func() time.Time) (*BudgetUseCaseImpl, error) {
+	b := &BudgetUseCaseImpl{
+		config:  config,
+		apiKeys: make(map[string]*spendWindow),
+		now:     clock,
+	}
+
+	now := b.now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	entries, err := ledger.Entries(monthStart, now.Add(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("error restaurando presupuesto: %v", err)
+	}
+
+	b.global.roll(now)
+	for _, entry := range entries {
+		b.add(&b.global, entry.Timestamp, entry.CostUSD)
+		if w := b.keyWindow(entry.APIKey); w != nil {
+			w.roll(now)
+			b.add(w, entry.Timestamp, entry.CostUSD)
+		}
+	}
+
+	return b, nil
+}
+
+// add suma un costo histórico a la ventana, solo al día si corresponde al día en curso
+func (b *BudgetUseCaseImpl) add(w *spendWindow, at time.Time, costUSD float64) {
+	w.monthlyUSD += costUSD
+	if at.UTC().Format("2006-01-02") == w.day {
+		w.dailyUSD += costUSD
+	}
+}
+
+// Check retorna el nivel de presupuesto más restrictivo entre el global y el de la API key
+func (b *BudgetUseCaseImpl) Check(ctx context.Context) BudgetLevel {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.global.roll(now)
+	level := b.global.level(b.config.Global)
+
+	if w := b.keyWindow(reqctx.APIKeyName(ctx)); w != nil {
+		w.roll(now)
+		level = mostRestrictive(level, w.level(b.config.PerAPIKey))
+	}
+
+	return level
+}
+
+// Record suma el costo de una solicitud al gasto global y al de la API key
+func (b *BudgetUseCaseImpl) Record(ctx context.Context, costUSD float64) {
+	if costUSD <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.global.roll(now)
+	b.global.dailyUSD += costUSD
+	b.global.monthlyUSD += costUSD
+
+	if w := b.keyWindow(reqctx.APIKeyName(ctx)); w != nil {
+		w.roll(now)
+		w.dailyUSD += costUSD
+		w.monthlyUSD += costUSD
+	}
+
+	log.Debug(ctx, "Costo registrado en presupuesto",
+		log.Float("costo", costUSD),
+		log.Float("daily_usd", b.global.dailyUSD),
+		log.Float("monthly_usd", b.global.monthlyUSD),
+	)
+}
+
+// GetStatus retorna el estado actual de los presupuestos
+func (b *BudgetUseCaseImpl) GetStatus(ctx context.Context) (*models.BudgetStatusResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.global.roll(now)
+
+	response := &models.BudgetStatusResponse{
+		Global:  usageOf(&b.global, b.config.Global),
+		APIKeys: make(map[string]models.BudgetUsage, len(b.apiKeys)),
+	}
+	for name, w := range b.apiKeys {
+		w.roll(now)
+		response.APIKeys[name] = usageOf(w, b.config.PerAPIKey)
+	}
+
+	return response, nil
+}
+
+// keyWindow retorna la ventana de gasto de la API key, o nil para clientes anónimos
+func (b *BudgetUseCaseImpl) keyWindow(apiKeyName string) *spendWindow {
+	if apiKeyName == "" {
+		return nil
+	}
+	w, ok := b.apiKeys[apiKeyName]
+	if !ok {
+		w = &spendWindow{}
+		b.apiKeys[apiKeyName] = w
+	}
+	return w
+}
+
+func mostRestrictive(a, b BudgetLevel) BudgetLevel {
+	if a == BudgetHardLimit || b == BudgetHardLimit {
+		return BudgetHardLimit
+	}
+	if a == BudgetSoftLimit || b == BudgetSoftLimit {
+		return BudgetSoftLimit
+	}
+	return BudgetOK
+}
+
+func usageOf(w *spendWindow, limits config.BudgetLimits) models.BudgetUsage {
+	return models.BudgetUsage{
+		Status:     string(w.level(limits)),
+		Day:        w.day,
+		DailyUSD:   w.dailyUSD,
+		Month:      w.month,
+		MonthlyUSD: w.monthlyUSD,
+		Limits: models.BudgetLimits{
+			DailySoftUSD:   limits.DailySoftUSD,
+			DailyHardUSD:   limits.DailyHardUSD,
+			MonthlySoftUSD: limits.MonthlySoftUSD,
+			MonthlyHardUSD: limits.MonthlyHardUSD,
+		},
+	}
+}
@@ -0,0 +1,244 @@
+package usecases
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+// testClock es un reloj que el test adelanta a mano
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+// newTestBudget crea el use case con un ledger vacío o con las entradas indicadas
+func newTestBudget(t *testing.T, cfg config.BudgetConfig, clock *testClock, entries ...models.UsageEntry) *BudgetUseCaseImpl {
+	t.Helper()
+
+	ledger, err := services.NewUsageLedger(filepath.Join(t.TempDir(), "usage.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ledger.Close() })
+	for _, entry := range entries {
+		if err := ledger.Append(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := newBudgetUseCase(cfg, ledger, clock.Now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSpendWindowLevel(t *testing.T) {
+	limits := config.BudgetLimits{DailySoftUSD: 1, DailyHardUSD: 2, MonthlySoftUSD: 10, MonthlyHardUSD: 20}
+
+	tests := []struct {
+		name    string
+		limits  config.BudgetLimits
+		daily   float64
+		monthly float64
+		want    BudgetLevel
+	}{
+		{name: "sin gasto", limits: limits, want: BudgetOK},
+		{name: "debajo del blando", limits: limits, daily: 0.99, monthly: 9.99, want: BudgetOK},
+		{name: "blando diario exacto", limits: limits, daily: 1, monthly: 1, want: BudgetSoftLimit},
+		{name: "blando mensual exacto", limits: limits, daily: 0, monthly: 10, want: BudgetSoftLimit},
+		{name: "duro diario exacto", limits: limits, daily: 2, monthly: 2, want: BudgetHardLimit},
+		{name: "duro mensual con diario bajo", limits: limits, daily: 0.5, monthly: 20, want: BudgetHardLimit},
+		{name: "límites en 0 no aplican", limits: config.BudgetLimits{}, daily: 1000, monthly: 1000, want: BudgetOK},
+		{name: "solo límite mensual", limits: config.BudgetLimits{MonthlyHardUSD: 5}, daily: 4, monthly: 5, want: BudgetHardLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := spendWindow{dailyUSD: tt.daily, monthlyUSD: tt.monthly}
+			if got := w.level(tt.limits); got != tt.want {
+				t.Errorf("level() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBudgetWindowBoundaries(t *testing.T) {
+	tests := []struct {
+		name        string
+		recordAt    time.Time
+		checkAt     time.Time
+		wantDaily   float64
+		wantMonthly float64
+		wantDay     string
+		wantMonth   string
+	}{
+		{
+			name:        "mismo día",
+			recordAt:    time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			checkAt:     time.Date(2026, 3, 10, 23, 59, 59, 0, time.UTC),
+			wantDaily:   1.5,
+			wantMonthly: 1.5,
+			wantDay:     "2026-03-10",
+			wantMonth:   "2026-03",
+		},
+		{
+			name:        "medianoche UTC reinicia el día",
+			recordAt:    time.Date(2026, 3, 10, 23, 59, 59, 0, time.UTC),
+			checkAt:     time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC),
+			wantDaily:   0,
+			wantMonthly: 1.5,
+			wantDay:     "2026-03-11",
+			wantMonth:   "2026-03",
+		},
+		{
+			name:        "fin de mes reinicia día y mes",
+			recordAt:    time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC),
+			checkAt:     time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			wantDaily:   0,
+			wantMonthly: 0,
+			wantDay:     "2026-02-01",
+			wantMonth:   "2026-02",
+		},
+		{
+			name:        "fin de febrero en año no bisiesto",
+			recordAt:    time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC),
+			checkAt:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantDaily:   0,
+			wantMonthly: 0,
+			wantDay:     "2026-03-01",
+			wantMonth:   "2026-03",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &testClock{now: tt.recordAt}
+			b := newTestBudget(t, config.BudgetConfig{}, clock)
+			ctx := reqctx.WithAPIKeyName(context.Background(), "ana")
+
+			b.Record(ctx, 1.5)
+			clock.now = tt.checkAt
+
+			status, err := b.GetStatus(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, got := range map[string]models.BudgetUsage{"global": status.Global, "ana": status.APIKeys["ana"]} {
+				if got.DailyUSD != tt.wantDaily || got.MonthlyUSD != tt.wantMonthly || got.Day != tt.wantDay || got.Month != tt.wantMonth {
+					t.Errorf("%s = %+v, want daily %v monthly %v day %s month %s", name, got, tt.wantDaily, tt.wantMonthly, tt.wantDay, tt.wantMonth)
+				}
+			}
+		})
+	}
+}
+
+func TestBudgetPerKeyAndGlobal(t *testing.T) {
+	cfg := config.BudgetConfig{
+		Global:    config.BudgetLimits{DailySoftUSD: 5, DailyHardUSD: 10},
+		PerAPIKey: config.BudgetLimits{DailySoftUSD: 1, DailyHardUSD: 2},
+	}
+	anonymous := context.Background()
+	ana := reqctx.WithAPIKeyName(context.Background(), "ana")
+	beto := reqctx.WithAPIKeyName(context.Background(), "beto")
+
+	tests := []struct {
+		name    string
+		records map[string]float64 // api key ("" es anónimo) -> costo
+		want    map[string]BudgetLevel
+	}{
+		{
+			name:    "sin gasto",
+			records: nil,
+			want:    map[string]BudgetLevel{"": BudgetOK, "ana": BudgetOK, "beto": BudgetOK},
+		},
+		{
+			name:    "el límite de una key no afecta a las demás",
+			records: map[string]float64{"ana": 2},
+			want:    map[string]BudgetLevel{"": BudgetOK, "ana": BudgetHardLimit, "beto": BudgetOK},
+		},
+		{
+			name:    "límite blando de key",
+			records: map[string]float64{"beto": 1},
+			want:    map[string]BudgetLevel{"": BudgetOK, "ana": BudgetOK, "beto": BudgetSoftLimit},
+		},
+		{
+			name:    "el gasto anónimo solo cuenta en el global",
+			records: map[string]float64{"": 5},
+			want:    map[string]BudgetLevel{"": BudgetSoftLimit, "ana": BudgetSoftLimit, "beto": BudgetSoftLimit},
+		},
+		{
+			name:    "el global suma todas las keys",
+			records: map[string]float64{"": 8, "ana": 1, "beto": 1},
+			want:    map[string]BudgetLevel{"": BudgetHardLimit, "ana": BudgetHardLimit, "beto": BudgetHardLimit},
+		},
+		{
+			name:    "costos no positivos se ignoran",
+			records: map[string]float64{"ana": -3},
+			want:    map[string]BudgetLevel{"": BudgetOK, "ana": BudgetOK, "beto": BudgetOK},
+		},
+	}
+
+	contexts := map[string]context.Context{"": anonymous, "ana": ana, "beto": beto}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &testClock{now: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)}
+			b := newTestBudget(t, cfg, clock)
+			for key, cost := range tt.records {
+				b.Record(contexts[key], cost)
+			}
+			for key, want := range tt.want {
+				if got := b.Check(contexts[key]); got != want {
+					t.Errorf("Check(%q) = %s, want %s", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBudgetRestoresFromLedger(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	entry := func(at time.Time, apiKey string, cost float64) models.UsageEntry {
+		return models.UsageEntry{Timestamp: at, APIKey: apiKey, TokenUsage: models.TokenUsage{CostUSD: cost}}
+	}
+
+	b := newTestBudget(t, config.BudgetConfig{}, &testClock{now: now},
+		entry(time.Date(2026, 2, 28, 23, 59, 59, 0, time.UTC), "ana", 100), // mes anterior
+		entry(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "ana", 1),       // inicio del mes
+		entry(time.Date(2026, 3, 9, 23, 59, 59, 0, time.UTC), "", 2),       // ayer
+		entry(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), "ana", 4),      // hoy a medianoche
+		entry(time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC), "beto", 8),    // hoy
+	)
+
+	status, err := b.GetStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		got         models.BudgetUsage
+		wantDaily   float64
+		wantMonthly float64
+	}{
+		{name: "global", got: status.Global, wantDaily: 12, wantMonthly: 15},
+		{name: "ana", got: status.APIKeys["ana"], wantDaily: 4, wantMonthly: 5},
+		{name: "beto", got: status.APIKeys["beto"], wantDaily: 8, wantMonthly: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.DailyUSD != tt.wantDaily || tt.got.MonthlyUSD != tt.wantMonthly {
+				t.Errorf("daily %v monthly %v, want daily %v monthly %v", tt.got.DailyUSD, tt.got.MonthlyUSD, tt.wantDaily, tt.wantMonthly)
+			}
+		})
+	}
+	if len(status.APIKeys) != 2 {
+		t.Errorf("APIKeys = %v, want solo ana y beto", status.APIKeys)
+	}
+}
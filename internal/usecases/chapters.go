@@ -0,0 +1,467 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/subtitles"
+	"github.com/ngrendenebos/scripts/transcribe-api/pkg/utils"
+)
+
+// chaptersFile es el archivo de capítulos dentro de la carpeta del video
+const chaptersFile = "chapters.json"
+
+// chapterTitlePrompt pide un título para cada capítulo detectado
+const chapterTitlePrompt = `Eres un asistente que titula los capítulos de videos en español.
+El mensaje del usuario contiene los %d capítulos consecutivos de un video%s, cada uno con su
+timestamp y un extracto de la transcripción.
+Escribe para cada capítulo un título breve (de 2 a 6 palabras) que describa su tema.
+Responde solo con un objeto JSON, sin texto adicional, con este formato:
+{"titles": ["título del capítulo 1", "título del capítulo 2"]}`
+
+// chapterTitleMaxTokens limita la respuesta del modelo al titular los capítulos
+const chapterTitleMaxTokens = 500
+
+// chapterExcerptTokens limita el extracto de cada capítulo enviado al modelo
+const chapterExcerptTokens = 300
+
+// transcriptWindow es un tramo de la transcripción de duración fija
+type transcriptWindow struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// ChaptersUseCaseImpl detecta, sirve y edita los capítulos de los videos
+type ChaptersUseCaseImpl struct {
+	openaiService *services.OpenAIService
+	chat          services.ChatModel
+	budget        BudgetUseCase
+	usage         UsageUseCase
+	config        config.Config
+	locks         videoLocks
+}
+
+// NewChaptersUseCase crea una nueva instancia del use case de capítulos
+func NewChaptersUseCase(openaiService *services.OpenAIService, chat services.ChatModel, budget BudgetUseCase, usage UsageUseCase, config config.Config) ChaptersUseCase {
+	return &ChaptersUseCaseImpl{
+		openaiService: openaiService,
+		chat:          chat,
+		budget:        budget,
+		usage:         usage,
+		config:        config,
+	}
+}
+
+// GetChapters retorna los capítulos guardados del video. Si no hay y está habilitado,
+// los genera desde la transcripción.
+func (s *ChaptersUseCaseImpl) GetChapters(ctx context.Context, id string) (*models.VideoChapters, error) {
+	if !utils.ValidateFilename(id) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+
+	chapters, err := s.readChapters(id)
+	if err != nil || chapters != nil {
+		return chapters, err
+	}
+
+	if !s.config.Chapters.GenerateOnDemand {
+		return nil, NewNotFoundError("not_found.chapters")
+	}
+
+	unlock := s.locks.lock(id)
+	defer unlock()
+
+	// Otra solicitud pudo haberlos generado mientras se esperaba el lock
+	chapters, err = s.readChapters(id)
+	if err != nil || chapters != nil {
+		return chapters, err
+	}
+
+	switch s.budget.Check(ctx) {
+	case BudgetHardLimit:
+		return nil, ErrBudgetExceeded
+	case BudgetSoftLimit:
+		log.Warn(ctx, "Límite blando de presupuesto alcanzado, no se generan los capítulos")
+		return nil, NewNotFoundError("not_found.chapters")
+	}
+
+	return s.generate(ctx, id)
+}
+
+// Regenerate detecta los capítulos desde la transcripción y reemplaza los guardados,
+// incluso si fueron editados
+func (s *ChaptersUseCaseImpl) Regenerate(ctx context.Context, id string) (*models.VideoChapters, error) {
+	if !utils.ValidateFilename(id) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+
+	unlock := s.locks.lock(id)
+	defer unlock()
+
+	if s.budget.Check(ctx) == BudgetHardLimit {
+		return nil, ErrBudgetExceeded
+	}
+
+	return s.generate(ctx, id)
+}
+
+// Update reemplaza los capítulos del video por los indicados
+func (s *ChaptersUseCaseImpl) Update(ctx context.Context, id string, req models.ChaptersUpdateRequest) (*models.VideoChapters, error) {
+	if !utils.ValidateFilename(id) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+	if len(req.Chapters) == 0 {
+		return nil, NewValidationError("validation.chapters_empty")
+	}
+
+	duration := videoDuration(s.config, id)
+	if duration == 0 {
+		return nil, NewNotFoundError("not_found.subtitles")
+	}
+
+	for i, chapter := range req.Chapters {
+		if strings.TrimSpace(chapter.Title) == "" {
+			return nil, NewValidationError("validation.chapter_title", i+1)
+		}
+		if chapter.StartSec < 0 || chapter.StartSec >= duration.Seconds() {
+			return nil, NewValidationError("validation.chapter_start", i+1, services.FormatTimestamp(duration.Seconds()))
+		}
+		if i > 0 && chapter.StartSec <= req.Chapters[i-1].StartSec {
+			return nil, NewValidationError("validation.chapter_order")
+		}
+	}
+
+	unlock := s.locks.lock(id)
+	defer unlock()
+
+	chapters := &models.VideoChapters{VideoID: id}
+	if existing, err := s.readChapters(id); err == nil && existing != nil {
+		chapters = existing
+	}
+
+	now := time.Now().UTC()
+	chapters.Source = models.ChaptersSourceEdited
+	chapters.UpdatedAt = &now
+	chapters.Chapters = make([]models.Chapter, len(req.Chapters))
+	for i, chapter := range req.Chapters {
+		end := duration.Seconds()
+		if i+1 < len(req.Chapters) {
+			end = req.Chapters[i+1].StartSec
+		}
+		chapters.Chapters[i] = models.Chapter{
+			StartSec:  chapter.StartSec,
+			EndSec:    end,
+			Timestamp: services.FormatTimestamp(chapter.StartSec),
+			Title:     strings.TrimSpace(chapter.Title),
+		}
+	}
+
+	if err := writeJSONFile(filepath.Join(s.config.VideosPath, id, chaptersFile), chapters); err != nil {
+		return nil, NewInternalError(err, "no se pudieron guardar los capítulos")
+	}
+
+	log.Info(ctx, "Capítulos editados", log.String("video", id), log.Int("chapters", len(chapters.Chapters)))
+
+	return chapters, nil
+}
+
+// readChapters lee chapters.json. Retorna nil si no existe.
+func (s *ChaptersUseCaseImpl) readChapters(id string) (*models.VideoChapters, error) {
+	data, err := os.ReadFile(filepath.Join(s.config.VideosPath, id, chaptersFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewInternalError(err, "no se pudo leer el archivo de capítulos")
+	}
+
+	var chapters models.VideoChapters
+	if err := json.Unmarshal(data, &chapters); err != nil {
+		return nil, NewInternalError(err, "no se pudo procesar el archivo de capítulos")
+	}
+	return &chapters, nil
+}
+
+// generate divide la transcripción en ventanas, corta donde cambia el tema entre
+// ventanas consecutivas, titula los capítulos y guarda el resultado junto al video
+func (s *ChaptersUseCaseImpl) generate(ctx context.Context, id string) (*models.VideoChapters, error) {
+	cues, err := readTranscript(s.config, id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := s.config.Chapters
+	windows := transcriptWindows(cues, time.Duration(cfg.WindowSeconds)*time.Second)
+	if len(windows) == 0 {
+		return nil, NewNotFoundError("not_found.transcript")
+	}
+
+	var costo float64
+	defer func() { s.budget.Record(ctx, costo) }()
+
+	starts := []int{0}
+	if len(windows) > 1 && cfg.MaxChapters > 1 {
+		texts := make([]string, len(windows))
+		for i, window := range windows {
+			texts[i] = window.Text
+		}
+
+		embeddings, embeddingUsage, err := s.openaiService.GenerateEmbeddings(ctx, texts)
+		if err != nil {
+			return nil, upstreamError(err, "error generando embeddings de la transcripción")
+		}
+		s.usage.Record(ctx, OperationChapters, embeddingUsage)
+		costo += embeddingUsage.CostUSD
+
+		starts = detectBoundaries(windows, embeddings, time.Duration(cfg.MinChapterSeconds)*time.Second, cfg.MaxChapters)
+	}
+
+	segments := make([]transcriptWindow, len(starts))
+	for i, start := range starts {
+		end := len(windows)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+
+		texts := make([]string, 0, end-start)
+		for _, window := range windows[start:end] {
+			texts = append(texts, window.Text)
+		}
+		segments[i] = transcriptWindow{
+			Start: windows[start].Start,
+			End:   windows[end-1].End,
+			Text:  strings.Join(texts, " "),
+		}
+	}
+	// El primer capítulo empieza con el video
+	segments[0].Start = 0
+
+	titles, chatUsage, err := s.titleChapters(ctx, id, segments)
+	costo += chatUsage.CostUSD
+	if err != nil {
+		return nil, upstreamError(err, "error titulando los capítulos")
+	}
+
+	now := time.Now().UTC()
+	chapters := &models.VideoChapters{
+		VideoID:     id,
+		Source:      models.ChaptersSourceGenerated,
+		Chapters:    make([]models.Chapter, len(segments)),
+		Model:       chatUsage.Model,
+		CostUSD:     costo,
+		GeneratedAt: &now,
+	}
+	for i, segment := range segments {
+		chapters.Chapters[i] = models.Chapter{
+			StartSec:  segment.Start.Seconds(),
+			EndSec:    segment.End.Seconds(),
+			Timestamp: services.FormatTimestamp(segment.Start.Seconds()),
+			Title:     titles[i],
+		}
+	}
+
+	if err := writeJSONFile(filepath.Join(s.config.VideosPath, id, chaptersFile), chapters); err != nil {
+		return nil, NewInternalError(err, "no se pudieron guardar los capítulos")
+	}
+
+	log.Info(ctx, "Capítulos generados",
+		log.String("video", id),
+		log.Int("windows", len(windows)),
+		log.Int("chapters", len(segments)),
+		log.Float("costo", costo),
+	)
+
+	return chapters, nil
+}
+
+// titleChapters pide al modelo de chat un título por capítulo
+func (s *ChaptersUseCaseImpl) titleChapters(ctx context.Context, id string, segments []transcriptWindow) ([]string, models.TokenUsage, error) {
+	titleHint := ""
+	if title := videoTitle(id); title != "" {
+		titleHint = fmt.Sprintf(" titulado %q", title)
+	}
+
+	var b strings.Builder
+	for i, segment := range segments {
+		fmt.Fprintf(&b, "Capítulo %d [%s]:\n%s\n\n", i+1,
+			services.FormatTimestamp(segment.Start.Seconds()),
+			services.TruncateToTokens(segment.Text, chapterExcerptTokens),
+		)
+	}
+
+	answer, usage, err := s.chat.Generate(ctx, services.ChatRequest{
+		SystemPrompt: fmt.Sprintf(chapterTitlePrompt, len(segments), titleHint),
+		Query:        b.String(),
+		MaxTokens:    chapterTitleMaxTokens,
+	})
+	// Una llamada fallida puede haberse cobrado igual
+	if err == nil || spent(usage) {
+		s.usage.Record(ctx, OperationChapters, usage)
+	}
+	if err != nil {
+		return nil, usage, err
+	}
+
+	var parsed struct {
+		Titles []string `json:"titles"`
+	}
+	if err := parseModelJSON(answer, &parsed); err != nil {
+		return nil, usage, err
+	}
+	if len(parsed.Titles) != len(segments) {
+		return nil, usage, fmt.Errorf("el modelo devolvió %d títulos para %d capítulos", len(parsed.Titles), len(segments))
+	}
+
+	titles := make([]string, len(parsed.Titles))
+	for i, title := range parsed.Titles {
+		titles[i] = strings.TrimSpace(title)
+		if titles[i] == "" {
+			titles[i] = fmt.Sprintf("Capítulo %d", i+1)
+		}
+	}
+	return titles, usage, nil
+}
+
+// transcriptWindows agrupa los cues en ventanas consecutivas de la duración indicada
+func transcriptWindows(cues []subtitles.Cue, size time.Duration) []transcriptWindow {
+	var windows []transcriptWindow
+	var texts []string
+	var current transcriptWindow
+
+	for _, cue := range cues {
+		text := strings.Join(strings.Fields(cue.Text), " ")
+		if text == "" {
+			continue
+		}
+		if len(texts) > 0 && cue.Start-current.Start >= size {
+			current.Text = strings.Join(texts, " ")
+			windows = append(windows, current)
+			texts = nil
+		}
+		if len(texts) == 0 {
+			current = transcriptWindow{Start: cue.Start}
+		}
+		texts = append(texts, text)
+		current.End = cue.End
+	}
+	if len(texts) > 0 {
+		current.Text = strings.Join(texts, " ")
+		windows = append(windows, current)
+	}
+
+	return windows
+}
+
+// detectBoundaries elige dónde empiezan los capítulos con el criterio de TextTiling:
+// compara cada par de bloques vecinos de ventanas y corta en los valles de similitud
+// más profundos, respetando la duración mínima y la cantidad máxima de capítulos.
+// Retorna los índices de las ventanas donde empieza cada capítulo, el primero es 0.
+func detectBoundaries(windows []transcriptWindow, embeddings [][]float32, minChapter time.Duration, maxChapters int) []int {
+	n := len(windows)
+
+	// similarity[i] compara las ventanas anteriores y posteriores al corte entre i e i+1
+	similarity := make([]float64, n-1)
+	for i := range similarity {
+		left := meanEmbedding(embeddings[max(0, i-1) : i+1])
+		right := meanEmbedding(embeddings[i+1 : min(n, i+3)])
+		similarity[i] = cosineSimilarity(left, right)
+	}
+
+	// La profundidad de un valle es cuánto sube la similitud a cada lado
+	depth := make([]float64, len(similarity))
+	var sum float64
+	for i, value := range similarity {
+		leftPeak, rightPeak := value, value
+		for j := i - 1; j >= 0 && similarity[j] >= leftPeak; j-- {
+			leftPeak = similarity[j]
+		}
+		for j := i + 1; j < len(similarity) && similarity[j] >= rightPeak; j++ {
+			rightPeak = similarity[j]
+		}
+		depth[i] = (leftPeak - value) + (rightPeak - value)
+		sum += depth[i]
+	}
+
+	mean := sum / float64(len(depth))
+	var variance float64
+	for _, d := range depth {
+		variance += (d - mean) * (d - mean)
+	}
+	// Solo se consideran los valles claramente más profundos que el promedio
+	cutoff := mean + math.Sqrt(variance/float64(len(depth)))/2
+
+	candidates := make([]int, 0, len(depth))
+	for i, d := range depth {
+		if d > 0 && d >= cutoff {
+			candidates = append(candidates, i)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return depth[candidates[a]] > depth[candidates[b]]
+	})
+
+	// El primer capítulo empieza en 0 y ninguno puede quedar más corto que minChapter
+	end := windows[n-1].End
+	starts := []int{0}
+	startTimes := []time.Duration{0}
+	for _, gap := range candidates {
+		if len(starts) == maxChapters {
+			break
+		}
+		start := windows[gap+1].Start
+		if end-start < minChapter {
+			continue
+		}
+		ok := true
+		for _, chosen := range startTimes {
+			if distance := start - chosen; distance < minChapter && distance > -minChapter {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			starts = append(starts, gap+1)
+			startTimes = append(startTimes, start)
+		}
+	}
+
+	sort.Ints(starts)
+	return starts
+}
+
+// meanEmbedding promedia varios embeddings
+func meanEmbedding(vectors [][]float32) []float32 {
+	mean := make([]float32, len(vectors[0]))
+	for _, vector := range vectors {
+		for i, value := range vector {
+			mean[i] += value / float32(len(vectors))
+		}
+	}
+	return mean
+}
+
+// cosineSimilarity calcula la similitud coseno entre dos embeddings
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
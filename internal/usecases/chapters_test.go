@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDetectBoundaries(t *testing.T) {
+	// topics arma ventanas de 30 segundos con un embedding por tema: las ventanas del
+	// mismo tema son idénticas y las de temas distintos, ortogonales
+	topics := func(ids ...int) ([]transcriptWindow, [][]float32) {
+		windows := make([]transcriptWindow, len(ids))
+		embeddings := make([][]float32, len(ids))
+		for i, id := range ids {
+			windows[i] = transcriptWindow{Start: time.Duration(i) * 30 * time.Second, End: time.Duration(i+1) * 30 * time.Second}
+			embeddings[i] = make([]float32, 4)
+			embeddings[i][id] = 1
+		}
+		return windows, embeddings
+	}
+
+	tests := []struct {
+		name        string
+		topics      []int
+		minChapter  time.Duration
+		maxChapters int
+		want        []int
+	}{
+		{
+			name:        "un solo tema",
+			topics:      []int{0, 0, 0, 0, 0, 0},
+			minChapter:  60 * time.Second,
+			maxChapters: 5,
+			want:        []int{0},
+		},
+		{
+			name:        "corta en cada cambio de tema",
+			topics:      []int{0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2},
+			minChapter:  60 * time.Second,
+			maxChapters: 5,
+			want:        []int{0, 4, 8},
+		},
+		{
+			name:        "respeta la cantidad máxima",
+			topics:      []int{0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2},
+			minChapter:  60 * time.Second,
+			maxChapters: 2,
+			want:        []int{0, 4},
+		},
+		{
+			name:        "descarta cortes más cerca que la duración mínima",
+			topics:      []int{0, 0, 0, 0, 1, 1, 2, 2, 2, 2, 2, 2},
+			minChapter:  90 * time.Second,
+			maxChapters: 5,
+			want:        []int{0, 4},
+		},
+		{
+			name:        "no deja un último capítulo corto",
+			topics:      []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1},
+			minChapter:  90 * time.Second,
+			maxChapters: 5,
+			want:        []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, embeddings := topics(tt.topics...)
+			got := detectBoundaries(windows, embeddings, tt.minChapter, tt.maxChapters)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("detectBoundaries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+// assembledContext es el contexto que entra en el prompt junto con lo que quedó afuera
+type assembledContext struct {
+	Fragments  []services.PromptFragment
+	Videos     []string
+	Tokens     int
+	Duplicates int
+	Dropped    int
+}
+
+// assembleContext elige los fragmentos que entran en el prompt dentro de maxTokens.
+// Recorre los resultados en orden (vienen ordenados por relevancia), descarta duplicados
+// y saltea los que no entran para seguir probando con los siguientes. Si el más relevante no entra
+// solo, se recorta para que el prompt tenga al menos un fragmento.
+func assembleContext(results []models.ChunkResponse, maxTokens int) assembledContext {
+	var assembled assembledContext
+	var selectedTexts []string
+	seenIDs := make(map[string]bool)
+	seenVideos := make(map[string]bool)
+
+	for _, result := range results {
+		text := strings.TrimSpace(result.Text)
+		if text == "" {
+			continue
+		}
+
+		normalized := normalizeText(text)
+		if seenIDs[result.ID] || isDuplicate(normalized, selectedTexts) {
+			assembled.Duplicates++
+			continue
+		}
+
+		header := fragmentHeader(result)
+		tokens := services.EstimateTokens(header) + services.EstimateTokens(text)
+		if assembled.Tokens+tokens > maxTokens {
+			if len(assembled.Fragments) > 0 {
+				assembled.Dropped++
+				continue
+			}
+			text = services.TruncateToTokens(text, maxTokens-services.EstimateTokens(header))
+			if text == "" {
+				assembled.Dropped++
+				continue
+			}
+			tokens = services.EstimateTokens(header) + services.EstimateTokens(text)
+		}
+
+		seenIDs[result.ID] = true
+		selectedTexts = append(selectedTexts, normalized)
+		assembled.Tokens += tokens
+		assembled.Fragments = append(assembled.Fragments, services.PromptFragment{
+			Index:      len(assembled.Fragments) + 1,
+			Header:     header,
+			Text:       text,
+			Video:      result.Video,
+			VideoTitle: result.Title,
+			StartSec:   result.StartSec,
+			Timestamp:  services.FormatTimestamp(result.StartSec),
+		})
+
+		if result.Title != "" && !seenVideos[result.Title] {
+			seenVideos[result.Title] = true
+			assembled.Videos = append(assembled.Videos, result.Title)
+		}
+	}
+
+	return assembled
+}
+
+// fragmentHeader arma el encabezado del fragmento con el título del video y el timestamp
+func fragmentHeader(result models.ChunkResponse) string {
+	title := result.Title
+	if title == "" {
+		title = result.Video
+	}
+	return fmt.Sprintf("%s (%s)", title, services.FormatTimestamp(result.StartSec))
+}
+
+// isDuplicate indica si el texto ya está seleccionado o contenido en otro fragmento
+// (los chunks de transcripción suelen solaparse)
+func isDuplicate(normalized string, selected []string) bool {
+	for _, other := range selected {
+		if strings.Contains(other, normalized) || strings.Contains(normalized, other) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
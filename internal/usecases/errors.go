@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+// ErrorKind clasifica los errores de los use cases. Su valor es el código estable
+// que reciben los clientes en ErrorResponse.Code.
+type ErrorKind string
+
+const (
+	KindValidation          ErrorKind = "validation_error"
+	KindNotFound            ErrorKind = "not_found"
+	KindUpstreamUnavailable ErrorKind = "upstream_unavailable"
+	KindQuotaExceeded       ErrorKind = "quota_exceeded"
+	KindBudgetExceeded      ErrorKind = "budget_exceeded"
+	KindInternal            ErrorKind = "internal_error"
+)
+
+// Error es un error de dominio con su clasificación. Los errores visibles para el
+// cliente llevan una key del catálogo de mensajes para poder traducirse.
+type Error struct {
+	Kind    ErrorKind
+	Key     string
+	Args    []interface{}
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Localized retorna el mensaje del error en el idioma del contexto
+func (e *Error) Localized(ctx context.Context) string {
+	if e.Key == "" {
+		return e.Message
+	}
+	return i18n.T(ctx, e.Key, e.Args...)
+}
+
+// ErrBudgetExceeded se retorna cuando se alcanzó un límite duro de presupuesto
+var ErrBudgetExceeded = newKeyedError(KindBudgetExceeded, "budget.exceeded")
+
+func newError(kind ErrorKind, err error, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// newKeyedError crea un error cuyo mensaje sale del catálogo; Message queda en el
+// idioma por defecto para los logs
+func newKeyedError(kind ErrorKind, key string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Key: key, Args: args, Message: i18n.Translate(i18n.Spanish, key, args...)}
+}
+
+// NewValidationError crea un error de validación con una key del catálogo de mensajes
+func NewValidationError(key string, args ...interface{}) error {
+	return newKeyedError(KindValidation, key, args...)
+}
+
+// NewNotFoundError crea un error de recurso inexistente con una key del catálogo de mensajes
+func NewNotFoundError(key string, args ...interface{}) error {
+	return newKeyedError(KindNotFound, key, args...)
+}
+
+// NewInternalError envuelve un error inesperado
+func NewInternalError(err error, format string, args ...interface{}) error {
+	return newError(KindInternal, err, format, args...)
+}
+
+// upstreamError clasifica un error de un servicio externo (OpenAI, Pinecone)
+func upstreamError(err error, format string, args ...interface{}) error {
+	kind := KindUpstreamUnavailable
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		kind = KindQuotaExceeded
+	}
+	return newError(kind, err, format, args...)
+}
+
+// KindOf retorna la clasificación de un error; los errores sin clasificar son internos
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
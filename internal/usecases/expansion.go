@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+// expansionPrompt pide al modelo de chat reformulaciones de la consulta, una por línea
+const expansionPrompt = `Eres un asistente que mejora búsquedas sobre transcripciones de videos en español.
+Dada la consulta del usuario, escribe %d reformulaciones distintas usando sinónimos, términos
+relacionados y frases completas que podrían decirse en un video sobre el tema.
+Responde solo con las reformulaciones, una por línea, sin numerarlas ni agregar explicaciones.`
+
+// expansionMaxTokens limita la respuesta del modelo al generar reformulaciones
+const expansionMaxTokens = 300
+
+// listMarker reconoce viñetas y numeración al inicio de una línea ("1.", "2)", "-", "*")
+var listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s*`)
+
+// rrfK es la constante de Reciprocal Rank Fusion: valores altos suavizan la
+// diferencia entre los primeros puestos de cada lista
+const rrfK = 60
+
+// expandQuery pide al modelo de chat n reformulaciones de la consulta
+func (s *SearchUseCaseImpl) expandQuery(ctx context.Context, query string, n int) ([]string, models.TokenUsage, error) {
+	answer, usage, err := s.chat.Generate(ctx, services.ChatRequest{
+		SystemPrompt: fmt.Sprintf(expansionPrompt, n),
+		Query:        query,
+		MaxTokens:    expansionMaxTokens,
+	})
+	if err != nil {
+		return nil, usage, err
+	}
+
+	return parseVariants(answer, query, n), usage, nil
+}
+
+// parseVariants extrae hasta n reformulaciones de la respuesta del modelo, sin
+// numeración ni viñetas y sin repetir la consulta original
+func parseVariants(answer, query string, n int) []string {
+	seen := map[string]bool{normalizeText(query): true}
+	variants := make([]string, 0, n)
+
+	for _, line := range strings.Split(answer, "\n") {
+		line = listMarker.ReplaceAllString(strings.TrimSpace(line), "")
+		line = strings.Trim(line, "\"'")
+		normalized := normalizeText(line)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		variants = append(variants, line)
+		if len(variants) == n {
+			break
+		}
+	}
+
+	return variants
+}
+
+// searchAll ejecuta una búsqueda vectorial por embedding en paralelo y fusiona los
+// rankings. Solo falla si fallan todas; las búsquedas fallidas se loggean.
+func (s *SearchUseCaseImpl) searchAll(ctx context.Context, embeddings [][]float32, topK int) ([]models.ChunkResponse, error) {
+	if len(embeddings) == 1 {
+		return s.pineconeService.Search(ctx, embeddings[0], topK)
+	}
+
+	lists := make([][]models.ChunkResponse, len(embeddings))
+	errs := make([]error, len(embeddings))
+
+	var wg sync.WaitGroup
+	for i, embedding := range embeddings {
+		wg.Add(1)
+		go func(i int, embedding []float32) {
+			defer wg.Done()
+			lists[i], errs[i] = s.pineconeService.Search(ctx, embedding, topK)
+		}(i, embedding)
+	}
+	wg.Wait()
+
+	var ok [][]models.ChunkResponse
+	var lastErr error
+	for i, err := range errs {
+		if err != nil {
+			log.Warn(ctx, "Error en búsqueda vectorial de una variante", log.Int("variant", i), log.Err(err))
+			lastErr = err
+			continue
+		}
+		ok = append(ok, lists[i])
+	}
+	if len(ok) == 0 {
+		return nil, lastErr
+	}
+
+	return fuseResults(ok, topK), nil
+}
+
+// fuseResults combina varios rankings con Reciprocal Rank Fusion y retorna los topK
+// mejores. Cada chunk conserva su mayor similitud para el filtro por score.
+func fuseResults(lists [][]models.ChunkResponse, topK int) []models.ChunkResponse {
+	type fused struct {
+		chunk models.ChunkResponse
+		score float64
+	}
+
+	byID := make(map[string]*fused)
+	var order []string
+	for _, list := range lists {
+		for rank, chunk := range list {
+			entry, exists := byID[chunk.ID]
+			if !exists {
+				entry = &fused{chunk: chunk}
+				byID[chunk.ID] = entry
+				order = append(order, chunk.ID)
+			}
+			entry.score += 1.0 / float64(rrfK+rank+1)
+			if chunk.Score > entry.chunk.Score {
+				entry.chunk.Score = chunk.Score
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return byID[order[i]].score > byID[order[j]].score
+	})
+	if len(order) > topK {
+		order = order[:topK]
+	}
+
+	results := make([]models.ChunkResponse, 0, len(order))
+	for _, id := range order {
+		results = append(results, byID[id].chunk)
+	}
+	return results
+}
+
+// shareOfCost estima la parte del costo de una llamada en lote que corresponde a
+// algunos de sus textos, proporcional a sus tokens estimados
+func shareOfCost(cost float64, texts []string, part []string) float64 {
+	total := 0
+	for _, text := range texts {
+		total += services.EstimateTokens(text)
+	}
+	if total == 0 {
+		return 0
+	}
+
+	partial := 0
+	for _, text := range part {
+		partial += services.EstimateTokens(text)
+	}
+	return cost * float64(partial) / float64(total)
+}
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+// videoLocks serializa por video las operaciones que generan y guardan archivos
+type videoLocks struct {
+	locks sync.Map // id del video -> *sync.Mutex
+}
+
+// lock toma el lock del video y retorna la función que lo libera
+func (l *videoLocks) lock(id string) func() {
+	value, _ := l.locks.LoadOrStore(id, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// videoTitle retorna el título del video en videos.json, o vacío si no está
+func videoTitle(id string) string {
+	videos, err := services.LoadVideos()
+	if err != nil {
+		return ""
+	}
+	return videos[id].Title
+}
+
+// writeJSONFile guarda v como JSON de forma atómica: un archivo a medio escribir
+// nunca reemplaza al anterior
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// parseModelJSON decodifica en v el objeto JSON de la respuesta del modelo, ignorando
+// texto o bloques de código alrededor
+func parseModelJSON(answer string, v interface{}) error {
+	start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}")
+	if start < 0 || end < start {
+		return fmt.Errorf("la respuesta del modelo no contiene un objeto JSON")
+	}
+	if err := json.Unmarshal([]byte(answer[start:end+1]), v); err != nil {
+		return fmt.Errorf("respuesta del modelo inválida: %v", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package usecases
 import (
 	"context"
 
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
 )
@@ -21,12 +22,12 @@ func (h *HealthUseCaseImpl) CheckHealth(ctx context.Context) (*models.HealthResp
 	if h.pineconeService == nil {
 		return &models.HealthResponse{
 			Status:  "error",
-			Message: "Servicio no inicializado",
+			Message: i18n.T(ctx, "health.not_initialized"),
 		}, nil
 	}
 
 	return &models.HealthResponse{
 		Status:  "healthy",
-		Message: "API funcionando correctamente",
+		Message: i18n.T(ctx, "health.ok"),
 	}, nil
 }
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+// accentFold reemplaza letras acentuadas por su versión sin acento, para que
+// "sesion" marque "sesión"
+var accentFold = map[rune]rune{
+	'á': 'a', 'à': 'a', 'ä': 'a', 'â': 'a', 'ã': 'a',
+	'é': 'e', 'è': 'e', 'ë': 'e', 'ê': 'e',
+	'í': 'i', 'ì': 'i', 'ï': 'i', 'î': 'i',
+	'ó': 'o', 'ò': 'o', 'ö': 'o', 'ô': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'ü': 'u', 'û': 'u',
+	'ñ': 'n', 'ç': 'c',
+}
+
+// highlightTerms separa la consulta en términos normalizados, sin repetir y
+// descartando los de un solo carácter
+func highlightTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range strings.FieldsFunc(query, isNotWordRune) {
+		term := foldWord(word)
+		if len([]rune(term)) < 2 || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// highlight retorna los tramos de text con palabras que empiezan con alguno de los
+// términos, sin distinguir mayúsculas ni acentos. Las posiciones son en caracteres.
+func highlight(text string, terms []string) []models.TextRange {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var ranges []models.TextRange
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		if isNotWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && !isNotWordRune(runes[end]) {
+			end++
+		}
+
+		word := foldWord(string(runes[start:end]))
+		for _, term := range terms {
+			if strings.HasPrefix(word, term) {
+				ranges = append(ranges, models.TextRange{Start: start, End: end})
+				break
+			}
+		}
+		start = end
+	}
+	return ranges
+}
+
+// foldWord pasa la palabra a minúsculas y sin acentos
+func foldWord(word string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if folded, ok := accentFold[r]; ok {
+			return folded
+		}
+		return r
+	}, word)
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+)
+
+func TestHighlightTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "vacía", query: "", want: nil},
+		{name: "minúsculas y sin acentos", query: "Sesión ÚNICA", want: []string{"sesion", "unica"}},
+		{name: "descarta un carácter y repetidos", query: "a sesion y sesión", want: []string{"sesion"}},
+		{name: "separa por puntuación", query: "go,rust;python", want: []string{"go", "rust", "python"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightTerms(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("highlightTerms(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  []models.TextRange
+	}{
+		{name: "sin términos", text: "hola mundo", terms: nil, want: nil},
+		{name: "sin coincidencias", text: "hola mundo", terms: []string{"adios"}, want: nil},
+		{name: "palabra completa", text: "hola mundo", terms: []string{"mundo"}, want: []models.TextRange{{Start: 5, End: 10}}},
+		{name: "prefijo marca la palabra entera", text: "las sesiones", terms: []string{"sesion"}, want: []models.TextRange{{Start: 4, End: 12}}},
+		{name: "no marca en medio de palabra", text: "consesion", terms: []string{"sesion"}, want: nil},
+		{
+			name:  "posiciones en caracteres, no bytes",
+			text:  "La Sesión ñandú",
+			terms: []string{"sesion", "nandu"},
+			want:  []models.TextRange{{Start: 3, End: 9}, {Start: 10, End: 15}},
+		},
+		{
+			name:  "varias apariciones y puntuación",
+			text:  "¿Go? go, GO.",
+			terms: []string{"go"},
+			want:  []models.TextRange{{Start: 1, End: 3}, {Start: 5, End: 7}, {Start: 9, End: 11}},
+		},
+		{name: "dígitos son parte de la palabra", text: "go1 22", terms: []string{"go"}, want: []models.TextRange{{Start: 0, End: 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.text, tt.terms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("highlight(%q, %v) = %v, want %v", tt.text, tt.terms, got, tt.want)
+			}
+		})
+	}
+}
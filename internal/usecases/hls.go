@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/pkg/utils"
+)
+
+// hlsPlaylistType es el Content-Type de las playlists HLS
+const hlsPlaylistType = "application/vnd.apple.mpegurl"
+
+// hlsContentTypes son los archivos que se sirven de una rendition HLS, por extensión
+var hlsContentTypes = map[string]string{
+	".m3u8": hlsPlaylistType,
+	".ts":   "video/mp2t",
+	".m4s":  "video/iso.segment",
+	".mp4":  "video/mp4",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".vtt":  "text/vtt",
+	".key":  "application/octet-stream",
+}
+
+// GetHLSFile retorna un archivo de la rendition HLS local del video. name es relativo
+// a la carpeta HLS y puede incluir subcarpetas (720p/segment_001.ts).
+func (v *VideoUseCaseImpl) GetHLSFile(ctx context.Context, id, name string) (*models.HLSFile, error) {
+	if !utils.ValidateFilename(id) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+	if !v.config.HLS.Enabled {
+		return nil, NewNotFoundError("not_found.hls")
+	}
+
+	name = strings.TrimPrefix(name, "/")
+	if name == "" || strings.Contains(name, "\\") || !filepath.IsLocal(name) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+	contentType, ok := hlsContentTypes[strings.ToLower(path.Ext(name))]
+	if !ok {
+		return nil, NewNotFoundError("not_found.hls")
+	}
+
+	filePath := filepath.Join(v.config.VideosPath, id, v.config.HLS.Dir, filepath.FromSlash(name))
+	info, err := os.Stat(filePath)
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return nil, NewNotFoundError("not_found.hls")
+	}
+	if err != nil {
+		return nil, NewInternalError(err, "no se pudo leer el archivo HLS")
+	}
+
+	return &models.HLSFile{
+		Path:        filePath,
+		ContentType: contentType,
+		Playlist:    contentType == hlsPlaylistType,
+	}, nil
+}
+
+// videoSources lista las fuentes de reproducción del video: las locales (HLS y
+// video.mp4) y la url de videos.json. Se ordenan por el formato preferido del video
+// y, dentro de cada formato, las locales primero.
+func videoSources(cfg config.Config, video models.Video) []models.VideoSource {
+	var sources []models.VideoSource
+
+	dir := filepath.Join(cfg.VideosPath, video.ID)
+	if cfg.HLS.Enabled && fileExists(filepath.Join(dir, cfg.HLS.Dir, cfg.HLS.MasterPlaylist)) {
+		sources = append(sources, models.VideoSource{
+			Type:     models.VideoSourceHLS,
+			URL:      fmt.Sprintf("/video/%s/hls/%s", video.ID, filepath.ToSlash(cfg.HLS.MasterPlaylist)),
+			MIMEType: hlsPlaylistType,
+			Local:    true,
+		})
+	}
+	if fileExists(filepath.Join(dir, "video.mp4")) {
+		sources = append(sources, models.VideoSource{
+			Type:     models.VideoSourceMP4,
+			URL:      fmt.Sprintf("/video/%s", video.ID),
+			MIMEType: "video/mp4",
+			Local:    true,
+		})
+	}
+	if video.URL != "" {
+		source := models.VideoSource{Type: models.VideoSourceMP4, URL: video.URL, MIMEType: "video/mp4"}
+		if strings.HasSuffix(strings.ToLower(strings.SplitN(video.URL, "?", 2)[0]), ".m3u8") {
+			source.Type, source.MIMEType = models.VideoSourceHLS, hlsPlaylistType
+		}
+		sources = append(sources, source)
+	}
+
+	prefer := video.Prefer
+	if prefer == "" {
+		prefer = models.VideoSourceHLS
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		if (sources[i].Type == prefer) != (sources[j].Type == prefer) {
+			return sources[i].Type == prefer
+		}
+		return sources[i].Local && !sources[j].Local
+	})
+
+	return sources
+}
+
+// fileExists indica si path existe y es un archivo
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+// Modos de recuperación
+const (
+	RetrievalStandard = "standard"
+	RetrievalHyDE     = "hyde"
+)
+
+// RetrievalModes son los modos de recuperación aceptados en retrieval_mode
+var RetrievalModes = []string{RetrievalStandard, RetrievalHyDE}
+
+// hydePrompt pide al modelo de chat un fragmento de transcripción hipotético que
+// responda la consulta, para buscar con texto parecido al indexado
+const hydePrompt = `Eres un experto que da charlas en video. Escribe un fragmento breve (3 a 5 oraciones)
+de la transcripción de un video en español en el que se responde la consulta del usuario.
+Escribe en primera persona y en tono hablado, como si fuera texto transcripto, sin títulos,
+sin listas y sin mencionar la consulta. Si no conoces la respuesta, escribe igualmente un
+fragmento plausible sobre el tema.`
+
+// hydeMaxTokens limita el largo del fragmento hipotético
+const hydeMaxTokens = 250
+
+// generateHypothetical pide al modelo de chat el fragmento hipotético para la consulta
+func (s *SearchUseCaseImpl) generateHypothetical(ctx context.Context, query string) (string, models.TokenUsage, error) {
+	passage, usage, err := s.chat.Generate(ctx, services.ChatRequest{
+		SystemPrompt: hydePrompt,
+		Query:        query,
+		MaxTokens:    hydeMaxTokens,
+	})
+	if err != nil {
+		return "", usage, err
+	}
+	return strings.TrimSpace(passage), usage, nil
+}
+
+// averageEmbeddings promedia dos embeddings y normaliza el resultado a norma 1
+func averageEmbeddings(a, b []float32) []float32 {
+	avg := make([]float32, len(a))
+	var norm float64
+	for i := range a {
+		avg[i] = (a[i] + b[i]) / 2
+		norm += float64(avg[i]) * float64(avg[i])
+	}
+
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		return avg
+	}
+	for i := range avg {
+		avg[i] = float32(float64(avg[i]) / norm)
+	}
+	return avg
+}
@@ -2,12 +2,32 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
 )
 
 type SearchUseCase interface {
-	Search(ctx context.Context, query string, topK int) (*models.SearchResponse, error)
+	Search(ctx context.Context, req models.SearchRequest) (*models.SearchResponse, error)
+}
+
+type PromptUseCase interface {
+	Resolve(ctx context.Context, version string) (string, error)
+	Render(ctx context.Context, version string, data services.PromptData) (string, error)
+	GetVersions(ctx context.Context) (*models.PromptVersionsResponse, error)
+	Reload(ctx context.Context) (*models.PromptVersionsResponse, error)
+}
+
+type BudgetUseCase interface {
+	Check(ctx context.Context) BudgetLevel
+	Record(ctx context.Context, costUSD float64)
+	GetStatus(ctx context.Context) (*models.BudgetStatusResponse, error)
+}
+
+type UsageUseCase interface {
+	Record(ctx context.Context, operation string, usage models.TokenUsage)
+	GetReport(ctx context.Context, from, to time.Time, groupBy []string) (*models.UsageReportResponse, error)
 }
 
 type HealthUseCase interface {
@@ -19,9 +39,31 @@ type StatsUseCase interface {
 }
 
 type VideoUseCase interface {
-	GetVideos(ctx context.Context) ([]byte, error)
+	GetVideos(ctx context.Context) (*models.VideosData, error)
 	GetVideo(ctx context.Context, id string) (string, error)
-	GetSubtitles(ctx context.Context, id string) (string, error)
-	GetThumbnail(ctx context.Context, id string) (string, error)
-	GetSummary(ctx context.Context, id string) (string, error)
+	GetHLSFile(ctx context.Context, id, name string) (*models.HLSFile, error)
+	GetSubtitles(ctx context.Context, id, language, acceptLanguage string) (*models.SubtitleTrack, error)
+	GetSubtitleTracks(ctx context.Context, id string) (*models.SubtitleTracksResponse, error)
+	GetTranscript(ctx context.Context, id string, query models.TranscriptQuery) (*models.TranscriptResponse, error)
+}
+
+type ThumbnailUseCase interface {
+	GetThumbnail(ctx context.Context, id string, query models.ThumbnailQuery) (*models.Thumbnail, error)
+}
+
+type SummaryUseCase interface {
+	GetSummary(ctx context.Context, id string) (*models.VideoSummary, error)
+	Regenerate(ctx context.Context, id string) (*models.VideoSummary, error)
+}
+
+type ChaptersUseCase interface {
+	GetChapters(ctx context.Context, id string) (*models.VideoChapters, error)
+	Regenerate(ctx context.Context, id string) (*models.VideoChapters, error)
+	Update(ctx context.Context, id string, req models.ChaptersUpdateRequest) (*models.VideoChapters, error)
+}
+
+type TranslationUseCase interface {
+	Translate(ctx context.Context, id string, req models.TranslationRequest) (*models.TranslationJob, error)
+	GetJob(ctx context.Context, jobID string) (*models.TranslationJob, error)
+	GetJobs(ctx context.Context) (*models.TranslationJobsResponse, error)
 }
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+// PromptUseCaseImpl resuelve y administra las versiones de templates de prompt
+type PromptUseCaseImpl struct {
+	store  *services.PromptStore
+	config config.PromptsConfig
+}
+
+// NewPromptUseCase crea una nueva instancia del use case de prompts
+func NewPromptUseCase(store *services.PromptStore, config config.PromptsConfig) PromptUseCase {
+	return &PromptUseCaseImpl{
+		store:  store,
+		config: config,
+	}
+}
+
+// Resolve elige la versión del prompt: primero la de la solicitud, luego la asignada
+// a la API key y por último la versión por defecto
+func (p *PromptUseCaseImpl) Resolve(ctx context.Context, version string) (string, error) {
+	if version == "" {
+		version = p.config.PerAPIKey[reqctx.APIKeyName(ctx)]
+	}
+	if version == "" {
+		version = p.config.DefaultVersion
+	}
+	if !p.store.Has(version) {
+		return "", NewValidationError("validation.prompt_version", version)
+	}
+
+	return version, nil
+}
+
+// Render genera el prompt de sistema con la versión indicada
+func (p *PromptUseCaseImpl) Render(ctx context.Context, version string, data services.PromptData) (string, error) {
+	prompt, err := p.store.Render(version, data)
+	if err != nil {
+		return "", NewInternalError(err, "error renderizando prompt")
+	}
+	return prompt, nil
+}
+
+// GetVersions retorna las versiones cargadas y la asignación por API key
+func (p *PromptUseCaseImpl) GetVersions(ctx context.Context) (*models.PromptVersionsResponse, error) {
+	return &models.PromptVersionsResponse{
+		DefaultVersion: p.config.DefaultVersion,
+		Versions:       p.store.Versions(),
+		PerAPIKey:      p.config.PerAPIKey,
+	}, nil
+}
+
+// Reload vuelve a leer los templates desde disco. Si alguno es inválido o falta una
+// versión configurada se siguen usando los anteriores.
+func (p *PromptUseCaseImpl) Reload(ctx context.Context) (*models.PromptVersionsResponse, error) {
+	versions, err := p.store.Reload()
+	if err != nil {
+		return nil, NewInternalError(err, "error recargando templates de prompt")
+	}
+	log.Info(ctx, "Templates de prompt recargados", log.Any("versions", versions))
+
+	return p.GetVersions(ctx)
+}
@@ -2,10 +2,12 @@ package usecases
 
 import (
 	"context"
-	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
 )
@@ -14,75 +16,311 @@ import (
 type SearchUseCaseImpl struct {
 	openaiService   *services.OpenAIService
 	pineconeService *services.PineconeService
+	chat            services.ChatModel
+	prompts         PromptUseCase
+	budget          BudgetUseCase
+	usage           UsageUseCase
 	config          config.Config
 }
 
 // NewSearchUseCase crea una nueva instancia del use case de búsqueda
-func NewSearchUseCase(openaiService *services.OpenAIService, pineconeService *services.PineconeService, config config.Config) SearchUseCase {
+func NewSearchUseCase(openaiService *services.OpenAIService, pineconeService *services.PineconeService, chat services.ChatModel, prompts PromptUseCase, budget BudgetUseCase, usage UsageUseCase, config config.Config) SearchUseCase {
 	return &SearchUseCaseImpl{
 		openaiService:   openaiService,
 		pineconeService: pineconeService,
+		chat:            chat,
+		prompts:         prompts,
+		budget:          budget,
+		usage:           usage,
 		config:          config,
 	}
 }
 
-// Search realiza una búsqueda vectorial y genera una respuesta con OpenAI
-func (s *SearchUseCaseImpl) Search(ctx context.Context, query string, topK int) (*models.SearchResponse, error) {
+// Search realiza una búsqueda vectorial y genera una respuesta con el proveedor de chat
+func (s *SearchUseCaseImpl) Search(ctx context.Context, req models.SearchRequest) (*models.SearchResponse, error) {
+	query := req.Query
+
 	// Validar parámetros
 	if query == "" {
-		return nil, fmt.Errorf("query no puede estar vacío")
+		return nil, NewValidationError("validation.query_empty")
 	}
 
-	if topK < 1 || topK > s.config.MaxTopK {
-		return nil, fmt.Errorf("top_k debe estar entre 1 y %d", s.config.MaxTopK)
+	opts, err := s.resolveOptions(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generar embedding
-	embedding, tokens, err := s.openaiService.GenerateEmbedding(ctx, query)
+	promptVersion, err := s.prompts.Resolve(ctx, req.PromptVersion)
 	if err != nil {
-		return nil, fmt.Errorf("error generando embedding: %v", err)
+		return nil, err
+	}
+
+	// Verificar presupuesto
+	budgetLevel := s.budget.Check(ctx)
+	if budgetLevel == BudgetHardLimit {
+		log.Warn(ctx, "Búsqueda rechazada por presupuesto")
+		return nil, ErrBudgetExceeded
+	}
+
+	// El gasto se registra aunque falle un paso posterior: las llamadas ya hechas se cobran
+	var costo float64
+	defer func() { s.budget.Record(ctx, costo) }()
+
+	// Expandir la consulta con reformulaciones si se pidió y el presupuesto lo permite
+	queries := []string{query}
+	var expansion *models.QueryExpansion
+	if opts.ExpandQuery && budgetLevel == BudgetOK {
+		variants, expansionUsage, err := s.expandQuery(ctx, query, opts.Expansions)
+		if spent(expansionUsage) {
+			s.usage.Record(ctx, OperationExpansion, expansionUsage)
+			costo += expansionUsage.CostUSD
+		}
+		if err != nil {
+			log.Warn(ctx, "Error expandiendo la consulta, se busca solo con la original", log.Err(err))
+		} else {
+			queries = append(queries, variants...)
+			expansion = &models.QueryExpansion{Variants: variants, CostUSD: expansionUsage.CostUSD}
+		}
+	}
+
+	// En modo HyDE se busca con un fragmento hipotético en lugar de la consulta
+	var hyde *models.HyDEPassage
+	if opts.RetrievalMode == RetrievalHyDE && budgetLevel == BudgetOK {
+		passage, hydeUsage, err := s.generateHypothetical(ctx, query)
+		// Un fragmento vacío o fallido igual se cobra; solo se descarta para la búsqueda
+		if spent(hydeUsage) {
+			s.usage.Record(ctx, OperationHyDE, hydeUsage)
+			costo += hydeUsage.CostUSD
+		}
+		switch {
+		case err != nil:
+			log.Warn(ctx, "Error generando fragmento hipotético, se busca con la consulta", log.Err(err))
+		case passage == "":
+			log.Warn(ctx, "El fragmento hipotético quedó vacío, se busca con la consulta")
+		default:
+			hyde = &models.HyDEPassage{Passage: passage, CostUSD: hydeUsage.CostUSD}
+		}
 	}
 
-	// Calcular costo inicial
-	costo := float64(tokens) * s.config.EmbeddingPricePer1K / 1000.0
+	opts = executedOptions(opts, expansion, hyde)
 
-	// Buscar en Pinecone
-	res, err := s.pineconeService.Search(ctx, embedding, topK)
+	// Generar los embeddings de todas las consultas en una sola llamada
+	texts := queries
+	if hyde != nil {
+		texts = append(append([]string{}, queries...), hyde.Passage)
+	}
+	embeddings, embeddingUsage, err := s.openaiService.GenerateEmbeddings(ctx, texts)
 	if err != nil {
-		return nil, fmt.Errorf("error en búsqueda: %v", err)
+		return nil, upstreamError(err, "error generando embedding")
+	}
+	s.usage.Record(ctx, OperationEmbedding, embeddingUsage)
+	costo += embeddingUsage.CostUSD
+	if expansion != nil {
+		expansion.CostUSD += shareOfCost(embeddingUsage.CostUSD, texts, queries[1:])
 	}
+	if hyde != nil {
+		hyde.CostUSD += shareOfCost(embeddingUsage.CostUSD, texts, []string{hyde.Passage})
 
-	filtrados := s.filterByScore(res, s.config.MinScoreThreshold)
+		// El fragmento reemplaza a la consulta original, o se promedia con ella
+		passageEmbedding := embeddings[len(embeddings)-1]
+		embeddings = embeddings[:len(embeddings)-1]
+		if opts.HyDEAverage {
+			embeddings[0] = averageEmbeddings(embeddings[0], passageEmbedding)
+		} else {
+			embeddings[0] = passageEmbedding
+		}
+	}
 
-	// Generar respuesta con OpenAI si hay resultados
+	// Buscar en Pinecone con cada consulta y fusionar los resultados
+	res, err := s.searchAll(ctx, embeddings, opts.TopK)
+	if err != nil {
+		return nil, upstreamError(err, "error en búsqueda vectorial")
+	}
+
+	filtrados := s.filterByScore(res, opts.MinScore)
+
+	// Generar respuesta si se pidió, hay resultados y el presupuesto lo permite
 	var generatedAnswer string
-	if len(filtrados) > 0 {
-		// Extraer textos de los resultados
-		contextTexts := make([]string, 0, len(filtrados))
-		for _, result := range filtrados {
-			if result.Text != "" {
-				contextTexts = append(contextTexts, result.Text)
-			}
-		}
+	var metadata *models.SearchMetadata
+	if !opts.GenerateAnswer {
+		log.Debug(ctx, "Respuesta generada deshabilitada para la solicitud")
+	} else if budgetLevel == BudgetSoftLimit {
+		log.Warn(ctx, "Límite blando de presupuesto alcanzado, se omite la respuesta generada")
+	} else if len(filtrados) > 0 {
+		// El presupuesto de contexto es el del modelo principal de la cadena
+		params := s.config.Generation.For(s.chat.Model())
+		assembled := assembleContext(filtrados, params.ContextTokens)
+		log.Info(ctx, "Contexto armado",
+			log.Int("fragments", len(assembled.Fragments)),
+			log.Int("tokens", assembled.Tokens),
+			log.Int("duplicates", assembled.Duplicates),
+			log.Int("dropped", assembled.Dropped),
+		)
 
-		// Generar respuesta con OpenAI
-		answer, chatTokens, err := s.openaiService.GenerateAnswer(ctx, query, contextTexts)
+		answer, chatUsage, err := s.generateAnswer(ctx, query, promptVersion, opts, assembled)
+		if spent(chatUsage) {
+			s.usage.Record(ctx, OperationChat, chatUsage)
+			costo += chatUsage.CostUSD
+		}
 		if err != nil {
-			log.Error(ctx, "Error generando respuesta", log.Err(err))
+			log.Error(ctx, "Error generando respuesta", log.Err(err), log.String("prompt_version", promptVersion))
 		} else {
 			generatedAnswer = answer
-			// Agregar costo de chat
-			costo += float64(chatTokens) * s.config.ChatPricePer1K / 1000.0
+			metadata = &models.SearchMetadata{
+				Model:            chatUsage.Model,
+				PromptVersion:    promptVersion,
+				ContextFragments: len(assembled.Fragments),
+				ContextTokens:    assembled.Tokens,
+			}
 		}
 	}
 
-	return &models.SearchResponse{
+	if !opts.IncludeText {
+		for i := range filtrados {
+			filtrados[i].Text = ""
+		}
+	}
+
+	response := &models.SearchResponse{
 		Query:           query,
 		Results:         filtrados,
 		Total:           len(filtrados),
 		GeneratedAnswer: generatedAnswer,
 		CostoUSD:        costo,
-	}, nil
+		Options:         opts,
+		Expansion:       expansion,
+		HyDE:            hyde,
+		Metadata:        metadata,
+	}
+	if budgetLevel != BudgetOK {
+		response.BudgetStatus = string(budgetLevel)
+	}
+
+	return response, nil
+}
+
+// generateAnswer renderiza el template de prompt con el contexto armado y genera la respuesta
+func (s *SearchUseCaseImpl) generateAnswer(ctx context.Context, query, promptVersion string, opts models.SearchOptions, assembled assembledContext) (string, models.TokenUsage, error) {
+	data := services.PromptData{
+		Query:     query,
+		Style:     opts.Style,
+		Language:  opts.Language,
+		Fragments: assembled.Fragments,
+		Videos:    assembled.Videos,
+	}
+
+	systemPrompt, err := s.prompts.Render(ctx, promptVersion, data)
+	if err != nil {
+		return "", models.TokenUsage{}, err
+	}
+
+	return s.chat.Generate(ctx, services.ChatRequest{
+		SystemPrompt: systemPrompt,
+		Query:        query,
+		MaxTokens:    opts.MaxAnswerTokens,
+	})
+}
+
+// resolveOptions aplica los valores por defecto a las opciones de la solicitud y
+// valida que estén dentro de los límites configurados. Las opciones deshabilitadas
+// por el servidor se apagan sin error; las respuestas informan el valor efectivo.
+func (s *SearchUseCaseImpl) resolveOptions(ctx context.Context, req models.SearchRequest) (models.SearchOptions, error) {
+	bounds := s.config.Search
+	opts := models.SearchOptions{
+		TopK:            s.config.DefaultTopK,
+		GenerateAnswer:  bounds.AnswersEnabled,
+		Style:           bounds.DefaultStyle,
+		MinScore:        s.config.MinScoreThreshold,
+		MaxAnswerTokens: s.config.Generation.For(s.chat.Model()).MaxTokens,
+		Language:        i18n.Language(ctx),
+		IncludeText:     bounds.TextEnabled,
+		Expansions:      bounds.DefaultExpansions,
+		RetrievalMode:   RetrievalStandard,
+		HyDEAverage:     bounds.HyDEAverage,
+	}
+	if opts.MaxAnswerTokens > bounds.MaxAnswerTokens {
+		opts.MaxAnswerTokens = bounds.MaxAnswerTokens
+	}
+	if !slices.Contains(bounds.AnswerLanguages, opts.Language) {
+		opts.Language = bounds.AnswerLanguages[0]
+	}
+
+	if req.TopK != 0 {
+		if req.TopK < 1 || req.TopK > s.config.MaxTopK {
+			return opts, NewValidationError("validation.top_k_range", s.config.MaxTopK)
+		}
+		opts.TopK = req.TopK
+	}
+
+	if req.GenerateAnswer != nil {
+		opts.GenerateAnswer = *req.GenerateAnswer && bounds.AnswersEnabled
+	}
+	if req.IncludeText != nil {
+		opts.IncludeText = *req.IncludeText && bounds.TextEnabled
+	}
+
+	if req.Style != "" {
+		if !slices.Contains(bounds.AllowedStyles, req.Style) {
+			return opts, NewValidationError("validation.style", req.Style, strings.Join(bounds.AllowedStyles, ", "))
+		}
+		opts.Style = req.Style
+	}
+
+	if req.MinScore != nil {
+		if *req.MinScore < bounds.MinScoreFloor || *req.MinScore > 1 {
+			return opts, NewValidationError("validation.min_score_range", bounds.MinScoreFloor)
+		}
+		opts.MinScore = *req.MinScore
+	}
+
+	if req.MaxAnswerTokens != 0 {
+		if req.MaxAnswerTokens < 1 || req.MaxAnswerTokens > bounds.MaxAnswerTokens {
+			return opts, NewValidationError("validation.max_answer_tokens_range", bounds.MaxAnswerTokens)
+		}
+		opts.MaxAnswerTokens = req.MaxAnswerTokens
+	}
+
+	if req.ExpandQuery != nil {
+		opts.ExpandQuery = *req.ExpandQuery && bounds.ExpansionEnabled
+	}
+	if req.Expansions != 0 {
+		if req.Expansions < 1 || req.Expansions > bounds.MaxExpansions {
+			return opts, NewValidationError("validation.expansions_range", bounds.MaxExpansions)
+		}
+		opts.Expansions = req.Expansions
+	}
+
+	if req.RetrievalMode != "" {
+		if !slices.Contains(RetrievalModes, req.RetrievalMode) {
+			return opts, NewValidationError("validation.retrieval_mode", req.RetrievalMode, strings.Join(RetrievalModes, ", "))
+		}
+		// Si HyDE está deshabilitado en el servidor se usa el modo estándar
+		if req.RetrievalMode == RetrievalStandard || bounds.HyDEEnabled {
+			opts.RetrievalMode = req.RetrievalMode
+		}
+	}
+	if req.HyDEAverage != nil {
+		opts.HyDEAverage = *req.HyDEAverage
+	}
+
+	if req.Language != "" {
+		if !slices.Contains(bounds.AnswerLanguages, req.Language) {
+			return opts, NewValidationError("validation.language", req.Language, strings.Join(bounds.AnswerLanguages, ", "))
+		}
+		opts.Language = req.Language
+	}
+
+	return opts, nil
+}
+
+// executedOptions ajusta las opciones a los pasos que efectivamente se ejecutaron: la
+// expansión y HyDE se omiten con presupuesto limitado o cuando fallan
+func executedOptions(opts models.SearchOptions, expansion *models.QueryExpansion, hyde *models.HyDEPassage) models.SearchOptions {
+	opts.ExpandQuery = expansion != nil
+	if hyde == nil {
+		opts.RetrievalMode = RetrievalStandard
+	}
+	return opts
 }
 
 // filterByScore filtra resultados por umbral de similitud
@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+func TestResolveOptionsExpansionAndRetrieval(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name          string
+		bounds        config.SearchConfig
+		req           models.SearchRequest
+		wantExpand    bool
+		wantExpansion int
+		wantMode      string
+		wantErr       bool
+	}{
+		{
+			name:          "valores por defecto",
+			bounds:        config.SearchConfig{ExpansionEnabled: true, HyDEEnabled: true},
+			wantExpansion: 2,
+			wantMode:      RetrievalStandard,
+		},
+		{
+			name:          "expansión y HyDE pedidos",
+			bounds:        config.SearchConfig{ExpansionEnabled: true, HyDEEnabled: true},
+			req:           models.SearchRequest{ExpandQuery: &yes, Expansions: 3, RetrievalMode: RetrievalHyDE},
+			wantExpand:    true,
+			wantExpansion: 3,
+			wantMode:      RetrievalHyDE,
+		},
+		{
+			name:          "expansión apagada por el cliente",
+			bounds:        config.SearchConfig{ExpansionEnabled: true},
+			req:           models.SearchRequest{ExpandQuery: &no},
+			wantExpansion: 2,
+			wantMode:      RetrievalStandard,
+		},
+		{
+			name:          "deshabilitados en el servidor",
+			bounds:        config.SearchConfig{},
+			req:           models.SearchRequest{ExpandQuery: &yes, RetrievalMode: RetrievalHyDE},
+			wantExpansion: 2,
+			wantMode:      RetrievalStandard,
+		},
+		{
+			name:    "demasiadas reformulaciones",
+			bounds:  config.SearchConfig{ExpansionEnabled: true},
+			req:     models.SearchRequest{Expansions: 5},
+			wantErr: true,
+		},
+		{
+			name:    "modo desconocido",
+			bounds:  config.SearchConfig{HyDEEnabled: true},
+			req:     models.SearchRequest{RetrievalMode: "hybrid"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds := tt.bounds
+			bounds.AnswerLanguages = []string{"es"}
+			bounds.DefaultExpansions = 2
+			bounds.MaxExpansions = 4
+
+			s := &SearchUseCaseImpl{
+				chat:   services.NewEchoChat("echo"),
+				config: config.Config{DefaultTopK: 5, MaxTopK: 20, Search: bounds},
+			}
+
+			opts, err := s.resolveOptions(context.Background(), tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("se esperaba error de validación")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveOptions: %v", err)
+			}
+			if opts.ExpandQuery != tt.wantExpand || opts.Expansions != tt.wantExpansion || opts.RetrievalMode != tt.wantMode {
+				t.Errorf("opciones = expand_query %v, expansions %d, retrieval_mode %q; want %v, %d, %q",
+					opts.ExpandQuery, opts.Expansions, opts.RetrievalMode, tt.wantExpand, tt.wantExpansion, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestExecutedOptions(t *testing.T) {
+	requested := models.SearchOptions{ExpandQuery: true, Expansions: 2, RetrievalMode: RetrievalHyDE, HyDEAverage: true}
+	expansion := &models.QueryExpansion{Variants: []string{"a", "b"}}
+	hyde := &models.HyDEPassage{Passage: "fragmento"}
+
+	tests := []struct {
+		name       string
+		expansion  *models.QueryExpansion
+		hyde       *models.HyDEPassage
+		wantExpand bool
+		wantMode   string
+	}{
+		{name: "todo se ejecutó", expansion: expansion, hyde: hyde, wantExpand: true, wantMode: RetrievalHyDE},
+		{name: "falló la expansión", hyde: hyde, wantExpand: false, wantMode: RetrievalHyDE},
+		{name: "falló HyDE", expansion: expansion, wantExpand: true, wantMode: RetrievalStandard},
+		{name: "presupuesto limitado", wantExpand: false, wantMode: RetrievalStandard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := executedOptions(requested, tt.expansion, tt.hyde)
+			if got.ExpandQuery != tt.wantExpand || got.RetrievalMode != tt.wantMode {
+				t.Errorf("expand_query %v, retrieval_mode %q; want %v, %q", got.ExpandQuery, got.RetrievalMode, tt.wantExpand, tt.wantMode)
+			}
+			if got.Expansions != requested.Expansions || got.HyDEAverage != requested.HyDEAverage {
+				t.Errorf("se modificaron otras opciones: %+v", got)
+			}
+		})
+	}
+}
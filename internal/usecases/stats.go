@@ -2,7 +2,6 @@ package usecases
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
 	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
@@ -24,7 +23,7 @@ func NewStatsUseCase(pineconeService *services.PineconeService) StatsUseCase {
 func (s *StatsUseCaseImpl) GetStats(ctx context.Context) (*models.StatsResponse, error) {
 	stats, err := s.pineconeService.GetStats(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error obteniendo estadísticas: %v", err)
+		return nil, upstreamError(err, "error obteniendo estadísticas")
 	}
 	return stats, nil
 }
@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/contenthash"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/subtitles"
+)
+
+// subtitleFilePattern reconoce subtitles.vtt, subtitles.srt y las pistas por idioma
+// como subtitles.en.vtt
+var subtitleFilePattern = regexp.MustCompile(`^subtitles(?:\.([a-z]{2,3}))?\.(` + strings.Join(subtitles.SourceFormats, "|") + `)$`)
+
+// listTracks retorna las pistas de subtítulos del video: la del idioma original primero
+// y el resto ordenadas por idioma. Los archivos sin idioma (subtitles.vtt) son del idioma
+// original. Si hay más de un archivo para un idioma se prefiere el que tiene idioma y
+// luego el formato según SourceFormats. Retorna nil si no existe la carpeta del video.
+func listTracks(cfg config.Config, id string) ([]models.SubtitleTrack, error) {
+	entries, err := os.ReadDir(filepath.Join(cfg.VideosPath, id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		track  models.SubtitleTrack
+		tagged bool
+	}
+	byLanguage := make(map[string]candidate)
+	for _, entry := range entries {
+		match := subtitleFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		language, tagged := match[1], match[1] != ""
+		if !tagged {
+			language = cfg.Subtitles.DefaultLanguage
+		}
+		current := candidate{
+			track: models.SubtitleTrack{
+				Language: language,
+				Format:   match[2],
+				Default:  language == cfg.Subtitles.DefaultLanguage,
+				URL:      fmt.Sprintf("/video/%s/subtitles?language=%s", id, language),
+				Path:     filepath.Join(cfg.VideosPath, id, entry.Name()),
+			},
+			tagged: tagged,
+		}
+
+		existing, ok := byLanguage[language]
+		if !ok || (current.tagged && !existing.tagged) ||
+			(current.tagged == existing.tagged && slices.Index(subtitles.SourceFormats, current.track.Format) < slices.Index(subtitles.SourceFormats, existing.track.Format)) {
+			byLanguage[language] = current
+		}
+	}
+
+	tracks := make([]models.SubtitleTrack, 0, len(byLanguage))
+	for _, c := range byLanguage {
+		// Las pistas WebVTT se sirven sin convertir: la URL lleva el hash del archivo
+		// para que el cliente pueda cachearla hasta que cambie
+		if c.track.Format == subtitles.FormatVTT {
+			if hash, err := contenthash.File(c.track.Path); err == nil {
+				c.track.URL += "&v=" + hash
+			}
+		}
+		tracks = append(tracks, c.track)
+	}
+	sort.Slice(tracks, func(i, j int) bool {
+		if tracks[i].Default != tracks[j].Default {
+			return tracks[i].Default
+		}
+		return tracks[i].Language < tracks[j].Language
+	})
+
+	return tracks, nil
+}
+
+// findTrack busca la pista de subtítulos del video en un idioma; vacío es el idioma original
+func findTrack(cfg config.Config, id, language string) (models.SubtitleTrack, error) {
+	if language == "" {
+		language = cfg.Subtitles.DefaultLanguage
+	}
+
+	tracks, err := listTracks(cfg, id)
+	if err != nil {
+		return models.SubtitleTrack{}, NewInternalError(err, "no se pudieron listar los subtítulos")
+	}
+	for _, track := range tracks {
+		if track.Language == language {
+			return track, nil
+		}
+	}
+	if len(tracks) == 0 {
+		return models.SubtitleTrack{}, NewNotFoundError("not_found.subtitles")
+	}
+	return models.SubtitleTrack{}, NewNotFoundError("not_found.subtitles_language", language)
+}
+
+// readTranscript lee los cues de la pista de subtítulos del video en un idioma;
+// vacío es el idioma original
+func readTranscript(cfg config.Config, id, language string) ([]subtitles.Cue, error) {
+	track, err := findTrack(cfg, id, language)
+	if err != nil {
+		return nil, err
+	}
+
+	cues, err := subtitles.ReadFile(track.Path)
+	if err != nil {
+		return nil, NewInternalError(err, "no se pudo leer la transcripción")
+	}
+	return cues, nil
+}
+
+// videoDuration retorna el fin del último cue de los subtítulos o, si no hay
+// subtítulos, la duración de videos.json. Retorna 0 si no se conoce.
+func videoDuration(cfg config.Config, id string) time.Duration {
+	if cues, err := readTranscript(cfg, id, ""); err == nil && len(cues) > 0 {
+		return cues[len(cues)-1].End
+	}
+
+	videos, err := services.LoadVideos()
+	if err != nil {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(videos[id].Duration, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
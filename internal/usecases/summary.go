@@ -0,0 +1,369 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/subtitles"
+	"github.com/ngrendenebos/scripts/transcribe-api/pkg/utils"
+)
+
+// Archivos del resumen dentro de la carpeta del video
+const (
+	summaryFile       = "summary.json"
+	manualSummaryFile = "summary.txt"
+)
+
+// summaryMapPrompt pide el resumen de una parte de la transcripción
+const summaryMapPrompt = `Eres un asistente que resume transcripciones de videos en español.
+El mensaje del usuario es la parte %d de %d de la transcripción de un video%s. Cada línea empieza
+con su timestamp entre corchetes.
+Responde solo con un objeto JSON, sin texto adicional, con este formato:
+{"tldr": "resumen de la parte en 2 o 3 oraciones", "key_points": [{"timestamp": "m:ss", "text": "idea principal"}], "topics": ["tema"]}
+Incluye hasta %d puntos clave, cada uno con el timestamp de la línea donde empieza la idea,
+y hasta 5 temas de una a tres palabras.`
+
+// summaryReducePrompt pide combinar los resúmenes de las partes en el resumen final
+const summaryReducePrompt = `Eres un asistente que resume videos en español.
+El mensaje del usuario contiene los resúmenes de las partes consecutivas de la transcripción
+de un video%s. Combínalos en un único resumen del video completo.
+Responde solo con un objeto JSON, sin texto adicional, con este formato:
+{"tldr": "resumen del video en 2 o 3 oraciones", "key_points": [{"timestamp": "m:ss", "text": "idea principal"}], "topics": ["tema"]}
+Incluye hasta %d puntos clave ordenados por tiempo, conservando los timestamps de los resúmenes,
+y hasta 8 temas sin repetir.`
+
+// summaryMaxTokens limita la respuesta del modelo en cada paso del resumen
+const summaryMaxTokens = 1000
+
+// summaryDraft es el resumen que devuelve el modelo, de una parte o del video completo
+type summaryDraft struct {
+	TLDR      string `json:"tldr"`
+	KeyPoints []struct {
+		Timestamp string `json:"timestamp"`
+		Text      string `json:"text"`
+	} `json:"key_points"`
+	Topics []string `json:"topics"`
+}
+
+// summaryCost acumula el consumo de las llamadas de un resumen
+type summaryCost struct {
+	mu    sync.Mutex
+	usd   float64
+	model string
+}
+
+func (c *summaryCost) add(usage models.TokenUsage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usd += usage.CostUSD
+	c.model = usage.Model
+}
+
+// SummaryUseCaseImpl genera y sirve resúmenes estructurados de los videos
+type SummaryUseCaseImpl struct {
+	chat   services.ChatModel
+	budget BudgetUseCase
+	usage  UsageUseCase
+	config config.Config
+	locks  videoLocks
+}
+
+// NewSummaryUseCase crea una nueva instancia del use case de resúmenes
+func NewSummaryUseCase(chat services.ChatModel, budget BudgetUseCase, usage UsageUseCase, config config.Config) SummaryUseCase {
+	return &SummaryUseCaseImpl{
+		chat:   chat,
+		budget: budget,
+		usage:  usage,
+		config: config,
+	}
+}
+
+// GetSummary retorna el resumen guardado del video, o el summary.txt escrito a mano.
+// Si no hay ninguno y está habilitado, lo genera desde la transcripción.
+func (s *SummaryUseCaseImpl) GetSummary(ctx context.Context, id string) (*models.VideoSummary, error) {
+	if !utils.ValidateFilename(id) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+
+	summary, err := s.readSummary(id)
+	if err != nil || summary != nil {
+		return summary, err
+	}
+
+	if !s.config.Summary.GenerateOnDemand {
+		return nil, NewNotFoundError("not_found.summary")
+	}
+
+	unlock := s.locks.lock(id)
+	defer unlock()
+
+	// Otra solicitud pudo haberlo generado mientras se esperaba el lock
+	summary, err = s.readSummary(id)
+	if err != nil || summary != nil {
+		return summary, err
+	}
+
+	switch s.budget.Check(ctx) {
+	case BudgetHardLimit:
+		return nil, ErrBudgetExceeded
+	case BudgetSoftLimit:
+		log.Warn(ctx, "Límite blando de presupuesto alcanzado, no se genera el resumen")
+		return nil, NewNotFoundError("not_found.summary")
+	}
+
+	return s.generate(ctx, id)
+}
+
+// Regenerate genera el resumen desde la transcripción y reemplaza el guardado
+func (s *SummaryUseCaseImpl) Regenerate(ctx context.Context, id string) (*models.VideoSummary, error) {
+	if !utils.ValidateFilename(id) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+
+	unlock := s.locks.lock(id)
+	defer unlock()
+
+	if s.budget.Check(ctx) == BudgetHardLimit {
+		return nil, ErrBudgetExceeded
+	}
+
+	return s.generate(ctx, id)
+}
+
+// readSummary lee summary.json o, si no existe, summary.txt. Retorna nil si no hay ninguno.
+func (s *SummaryUseCaseImpl) readSummary(id string) (*models.VideoSummary, error) {
+	dir := filepath.Join(s.config.VideosPath, id)
+
+	data, err := os.ReadFile(filepath.Join(dir, summaryFile))
+	if err == nil {
+		var summary models.VideoSummary
+		if err := json.Unmarshal(data, &summary); err != nil {
+			return nil, NewInternalError(err, "no se pudo procesar el archivo de resumen")
+		}
+		return &summary, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, NewInternalError(err, "no se pudo leer el archivo de resumen")
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, manualSummaryFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewInternalError(err, "no se pudo leer el archivo de resumen")
+	}
+
+	return &models.VideoSummary{
+		VideoID:   id,
+		Title:     videoTitle(id),
+		Source:    models.SummarySourceManual,
+		TLDR:      strings.TrimSpace(string(content)),
+		KeyPoints: []models.SummaryPoint{},
+		Topics:    []string{},
+	}, nil
+}
+
+// generate resume cada parte de la transcripción en paralelo (map), combina los
+// resúmenes parciales (reduce) y guarda el resultado junto al video
+func (s *SummaryUseCaseImpl) generate(ctx context.Context, id string) (*models.VideoSummary, error) {
+	cues, err := readTranscript(s.config, id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := chunkTranscript(cues, s.config.Summary.ChunkTokens)
+	if len(chunks) == 0 {
+		return nil, NewNotFoundError("not_found.transcript")
+	}
+
+	title := videoTitle(id)
+	titleHint := ""
+	if title != "" {
+		titleHint = fmt.Sprintf(" titulado %q", title)
+	}
+	maxPoints := s.config.Summary.MaxKeyPoints
+
+	cost := &summaryCost{}
+	defer func() { s.budget.Record(ctx, cost.usd) }()
+
+	drafts := make([]summaryDraft, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, s.config.Summary.Concurrency)
+
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			prompt := fmt.Sprintf(summaryMapPrompt, i+1, len(chunks), titleHint, maxPoints)
+			drafts[i], errs[i] = s.summarize(ctx, prompt, chunk, cost)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, upstreamError(err, "error resumiendo la transcripción")
+	}
+
+	final := drafts[0]
+	if len(drafts) > 1 {
+		prompt := fmt.Sprintf(summaryReducePrompt, titleHint, maxPoints)
+		final, err = s.summarize(ctx, prompt, formatDrafts(drafts), cost)
+		if err != nil {
+			return nil, upstreamError(err, "error combinando los resúmenes")
+		}
+	}
+
+	now := time.Now().UTC()
+	summary := &models.VideoSummary{
+		VideoID:     id,
+		Title:       title,
+		Source:      models.SummarySourceGenerated,
+		TLDR:        strings.TrimSpace(final.TLDR),
+		KeyPoints:   keyPoints(final, cues[len(cues)-1].End, maxPoints),
+		Topics:      topics(final.Topics),
+		Model:       cost.model,
+		Chunks:      len(chunks),
+		CostUSD:     cost.usd,
+		GeneratedAt: &now,
+	}
+
+	if err := writeJSONFile(filepath.Join(s.config.VideosPath, id, summaryFile), summary); err != nil {
+		return nil, NewInternalError(err, "no se pudo guardar el resumen")
+	}
+
+	log.Info(ctx, "Resumen generado",
+		log.String("video", id),
+		log.Int("chunks", len(chunks)),
+		log.Float("costo", cost.usd),
+	)
+
+	return summary, nil
+}
+
+// summarize pide un resumen al modelo de chat y registra su consumo
+func (s *SummaryUseCaseImpl) summarize(ctx context.Context, prompt, text string, cost *summaryCost) (summaryDraft, error) {
+	answer, usage, err := s.chat.Generate(ctx, services.ChatRequest{
+		SystemPrompt: prompt,
+		Query:        text,
+		MaxTokens:    summaryMaxTokens,
+	})
+	// Una llamada fallida puede haberse cobrado igual
+	if err == nil || spent(usage) {
+		s.usage.Record(ctx, OperationSummary, usage)
+		cost.add(usage)
+	}
+	if err != nil {
+		return summaryDraft{}, err
+	}
+
+	return parseDraft(answer)
+}
+
+// parseDraft extrae el resumen de la respuesta del modelo
+func parseDraft(answer string) (summaryDraft, error) {
+	var draft summaryDraft
+	err := parseModelJSON(answer, &draft)
+	return draft, err
+}
+
+// chunkTranscript arma partes de hasta maxTokens tokens estimados, con una línea por
+// cue precedida por su timestamp
+func chunkTranscript(cues []subtitles.Cue, maxTokens int) []string {
+	var chunks []string
+	var b strings.Builder
+	tokens := 0
+
+	for _, cue := range cues {
+		text := strings.Join(strings.Fields(cue.Text), " ")
+		if text == "" {
+			continue
+		}
+		line := fmt.Sprintf("[%s] %s\n", services.FormatTimestamp(cue.Start.Seconds()), text)
+		lineTokens := services.EstimateTokens(line)
+
+		if tokens > 0 && tokens+lineTokens > maxTokens {
+			chunks = append(chunks, b.String())
+			b.Reset()
+			tokens = 0
+		}
+		b.WriteString(line)
+		tokens += lineTokens
+	}
+	if tokens > 0 {
+		chunks = append(chunks, b.String())
+	}
+
+	return chunks
+}
+
+// formatDrafts arma el texto de entrada del paso de reducción
+func formatDrafts(drafts []summaryDraft) string {
+	var b strings.Builder
+	for i, draft := range drafts {
+		fmt.Fprintf(&b, "Parte %d:\nResumen: %s\nPuntos clave:\n", i+1, draft.TLDR)
+		for _, point := range draft.KeyPoints {
+			fmt.Fprintf(&b, "[%s] %s\n", point.Timestamp, point.Text)
+		}
+		fmt.Fprintf(&b, "Temas: %s\n\n", strings.Join(draft.Topics, ", "))
+	}
+	return b.String()
+}
+
+// keyPoints normaliza los puntos clave del modelo: descarta los que no tienen un
+// timestamp válido dentro del video y los ordena por tiempo
+func keyPoints(draft summaryDraft, duration time.Duration, max int) []models.SummaryPoint {
+	points := make([]models.SummaryPoint, 0, len(draft.KeyPoints))
+	for _, point := range draft.KeyPoints {
+		text := strings.TrimSpace(point.Text)
+		start, err := subtitles.ParseTimestamp(strings.Trim(strings.TrimSpace(point.Timestamp), "[]"))
+		if text == "" || err != nil || start > duration {
+			continue
+		}
+		points = append(points, models.SummaryPoint{
+			StartSec:  start.Seconds(),
+			Timestamp: services.FormatTimestamp(start.Seconds()),
+			Text:      text,
+		})
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].StartSec < points[j].StartSec
+	})
+	if len(points) > max {
+		points = points[:max]
+	}
+	return points
+}
+
+// topics descarta temas vacíos y repetidos
+func topics(values []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(values))
+	for _, topic := range values {
+		topic = strings.TrimSpace(topic)
+		key := strings.ToLower(topic)
+		if topic == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, topic)
+	}
+	return result
+}
@@ -0,0 +1,313 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+	"github.com/ngrendenebos/scripts/transcribe-api/pkg/utils"
+)
+
+// placeholderSVG es la miniatura genérica de los videos sin miniatura ni placeholder
+const placeholderSVG = `<svg width="%d" height="%d" viewBox="0 0 320 180" preserveAspectRatio="xMidYMid slice" xmlns="http://www.w3.org/2000/svg">
+	<rect width="320" height="180" fill="#374151"/>
+	<text x="160" y="90" text-anchor="middle" fill="white" font-family="Arial" font-size="24">🎥</text>
+</svg>`
+
+// imageContentTypes son los Content-Type de las miniaturas por formato o extensión
+var imageContentTypes = map[string]string{
+	services.ImageJPEG: "image/jpeg",
+	services.ImagePNG:  "image/png",
+	".jpg":             "image/jpeg",
+	".jpeg":            "image/jpeg",
+	".png":             "image/png",
+	".svg":             "image/svg+xml",
+}
+
+type ThumbnailUseCaseImpl struct {
+	config  config.Config
+	locks   videoLocks
+	sources videoSourceCache
+}
+
+// NewThumbnailUseCase crea una nueva instancia del use case de miniaturas
+func NewThumbnailUseCase(config config.Config) ThumbnailUseCase {
+	return &ThumbnailUseCaseImpl{
+		config: config,
+	}
+}
+
+// GetThumbnail retorna la miniatura del video en el tamaño y formato pedidos. Si el
+// video no tiene miniatura se usa el placeholder de su source, el placeholder general
+// o un SVG genérico.
+func (s *ThumbnailUseCaseImpl) GetThumbnail(ctx context.Context, id string, query models.ThumbnailQuery) (*models.Thumbnail, error) {
+	if !utils.ValidateFilename(id) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+
+	width, height, err := s.dimensions(query)
+	if err != nil {
+		return nil, err
+	}
+	format := query.Format
+	if format == "" {
+		format = services.ImageJPEG
+	}
+
+	source := filepath.Join(s.config.VideosPath, id, "thumbnail.jpg")
+	if _, err := os.Stat(source); err == nil {
+		return s.image(ctx, source, width, height, format)
+	} else if !os.IsNotExist(err) {
+		return nil, NewInternalError(err, "no se pudo leer la miniatura")
+	}
+
+	placeholder := s.placeholder(ctx, id)
+	if placeholder == "" {
+		if width == 0 && height == 0 {
+			width, height = 320, 180
+		}
+		if width == 0 {
+			width = height * 16 / 9
+		}
+		if height == 0 {
+			height = width * 9 / 16
+		}
+		return &models.Thumbnail{
+			Content:     []byte(fmt.Sprintf(placeholderSVG, width, height)),
+			ContentType: imageContentTypes[".svg"],
+			Placeholder: true,
+		}, nil
+	}
+
+	// Un placeholder SVG se sirve tal cual: el navegador lo escala
+	if strings.EqualFold(filepath.Ext(placeholder), ".svg") {
+		return &models.Thumbnail{Path: placeholder, ContentType: imageContentTypes[".svg"], Placeholder: true}, nil
+	}
+
+	thumbnail, err := s.image(ctx, placeholder, width, height, format)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail.Placeholder = true
+	return thumbnail, nil
+}
+
+// dimensions resuelve el tamaño pedido. 0 en una dimensión conserva la proporción y
+// 0 en las dos es el tamaño original.
+func (s *ThumbnailUseCaseImpl) dimensions(query models.ThumbnailQuery) (int, int, error) {
+	cfg := s.config.Thumbnails
+	if query.Size != "" {
+		size, ok := cfg.Presets[query.Size]
+		if !ok {
+			presets := make([]string, 0, len(cfg.Presets))
+			for name := range cfg.Presets {
+				presets = append(presets, name)
+			}
+			sort.Strings(presets)
+			return 0, 0, NewValidationError("validation.thumbnail_size", query.Size, strings.Join(presets, ", "))
+		}
+		return size.Width, size.Height, nil
+	}
+
+	width, err := parseDimension("width", query.Width, cfg.MaxWidth, cfg.SizeStep)
+	if err != nil {
+		return 0, 0, err
+	}
+	height, err := parseDimension("height", query.Height, cfg.MaxHeight, cfg.SizeStep)
+	if err != nil {
+		return 0, 0, err
+	}
+	return width, height, nil
+}
+
+// parseDimension parsea un ancho o alto entre 1 y limit; vacío es 0. El valor se
+// redondea hacia arriba al múltiplo de step (sin pasar limit) para acotar las variantes.
+func parseDimension(name, value string, limit, step int) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > limit {
+		return 0, NewValidationError("validation.thumbnail_dimension", name, limit)
+	}
+	return min(limit, (n+step-1)/step*step), nil
+}
+
+// placeholder retorna la imagen a usar para un video sin miniatura, según su source
+func (s *ThumbnailUseCaseImpl) placeholder(ctx context.Context, id string) string {
+	cfg := s.config.Thumbnails
+	if len(cfg.SourcePlaceholders) > 0 {
+		source, err := s.sources.get(id)
+		if err != nil {
+			log.Warn(ctx, "No se pudo leer el source del video", log.String("video", id), log.Err(err))
+		} else if path, ok := cfg.SourcePlaceholders[source]; ok {
+			return path
+		}
+	}
+	return cfg.Placeholder
+}
+
+// videoSourceCache guarda el source de cada video de videos.json. Se relee solo cuando
+// cambia la fecha de modificación del archivo.
+type videoSourceCache struct {
+	mu      sync.Mutex
+	modTime time.Time
+	sources map[string]string // id -> source
+}
+
+// get retorna el source del video
+func (c *videoSourceCache) get(id string) (string, error) {
+	info, err := os.Stat("videos.json")
+	if err != nil {
+		return "", fmt.Errorf("error leyendo videos.json: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sources == nil || !c.modTime.Equal(info.ModTime()) {
+		videos, err := services.LoadVideos()
+		if err != nil {
+			return "", err
+		}
+		c.sources = make(map[string]string, len(videos))
+		for videoID, video := range videos {
+			c.sources[videoID] = video.Source
+		}
+		c.modTime = info.ModTime()
+	}
+	return c.sources[id], nil
+}
+
+// image retorna la imagen source en el tamaño y formato pedidos. El original se sirve
+// sin procesar si ya cumple; las variantes se generan una vez y se guardan en el cache.
+func (s *ThumbnailUseCaseImpl) image(ctx context.Context, source string, width, height int, format string) (*models.Thumbnail, error) {
+	contentType := imageContentTypes[format]
+	if width == 0 && height == 0 && imageContentTypes[strings.ToLower(filepath.Ext(source))] == contentType {
+		return &models.Thumbnail{Path: source, ContentType: contentType}, nil
+	}
+
+	path, err := s.variant(ctx, source, width, height, format)
+	if err != nil {
+		return nil, NewInternalError(err, "no se pudo generar la miniatura")
+	}
+	return &models.Thumbnail{Path: path, ContentType: contentType}, nil
+}
+
+// variant retorna la ruta de la variante en el cache, generándola si no existe. El
+// nombre incluye la fecha de modificación del original, así un original nuevo invalida
+// sus variantes.
+func (s *ThumbnailUseCaseImpl) variant(ctx context.Context, source string, width, height int, format string) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha1.Sum([]byte(source))
+	key := hex.EncodeToString(sum[:8])
+	dir := filepath.Join(s.config.Thumbnails.CacheDir, key)
+	size := fmt.Sprintf("%dx%d", width, height)
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d.%s", size, info.ModTime().UnixNano(), format))
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	unlock := s.locks.lock(key)
+	defer unlock()
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	img, err := services.DecodeImageFile(source)
+	if err != nil {
+		return "", err
+	}
+	resized := services.ResizeImage(img, width, height)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if err := services.EncodeImage(file, resized, format, s.config.Thumbnails.Quality); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return "", err
+	}
+
+	// Descartar las variantes de versiones anteriores del original
+	stale, _ := filepath.Glob(filepath.Join(dir, size+"-*."+format))
+	for _, old := range stale {
+		if old != path {
+			os.Remove(old)
+		}
+	}
+	s.evictVariants(ctx, dir)
+
+	log.Debug(ctx, "Variante de miniatura generada",
+		log.String("source", source),
+		log.String("size", size),
+		log.String("format", format),
+	)
+	return path, nil
+}
+
+// evictVariants borra las variantes más viejas del directorio de un original cuando
+// superan thumbnails.max_variants. Una variante que se está sirviendo ya está abierta;
+// si se borra antes de abrirse, el handler la pide de nuevo y se regenera.
+func (s *ThumbnailUseCaseImpl) evictVariants(ctx context.Context, dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Warn(ctx, "No se pudo listar el cache de miniaturas", log.String("dir", dir), log.Err(err))
+		return
+	}
+
+	type cached struct {
+		name    string
+		modTime time.Time
+	}
+	variants := make([]cached, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) == ".tmp" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		variants = append(variants, cached{name: entry.Name(), modTime: info.ModTime()})
+	}
+
+	excess := len(variants) - s.config.Thumbnails.MaxVariants
+	if excess <= 0 {
+		return
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].modTime.Before(variants[j].modTime)
+	})
+	for _, variant := range variants[:excess] {
+		os.Remove(filepath.Join(dir, variant.name))
+	}
+	log.Debug(ctx, "Variantes de miniatura descartadas", log.String("dir", dir), log.Int("count", excess))
+}
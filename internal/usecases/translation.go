@@ -0,0 +1,384 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/subtitles"
+	"github.com/ngrendenebos/scripts/transcribe-api/pkg/utils"
+)
+
+// translationPrompt pide traducir un lote de cues, con los anteriores como contexto
+const translationPrompt = `Eres un traductor profesional de subtítulos. Traduce de %s a %s los cues del mensaje
+del usuario, que es un objeto JSON.
+"context" contiene los cues anteriores ya traducidos, solo como referencia: no los traduzcas.
+Traduce cada elemento de "cues" por separado, respetando el sentido en el contexto de la charla,
+el tono hablado, los nombres propios y los términos técnicos. Cada traducción debe tener como
+máximo %d caracteres para poder leerse durante el cue.
+Responde solo con un objeto JSON, sin texto adicional, con este formato:
+{"translations": [{"id": 1, "text": "traducción"}]}
+Incluye exactamente un elemento por cada cue, con el mismo id.`
+
+// translationJobTTL es cuánto se conservan los jobs terminados
+const translationJobTTL = 24 * time.Hour
+
+// languagePattern es el formato de los idiomas de las pistas de subtítulos
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// languageNames son los nombres usados en el prompt de traducción
+var languageNames = map[string]string{
+	"es": "español",
+	"en": "inglés",
+	"pt": "portugués",
+	"fr": "francés",
+	"de": "alemán",
+	"it": "italiano",
+	"ca": "catalán",
+}
+
+// translationCue es un cue en la entrada y la salida del modelo
+type translationCue struct {
+	ID          int    `json:"id,omitempty"`
+	Text        string `json:"text"`
+	Translation string `json:"translation,omitempty"`
+}
+
+// TranslationUseCaseImpl traduce pistas de subtítulos en segundo plano
+type TranslationUseCaseImpl struct {
+	chat   services.ChatModel
+	budget BudgetUseCase
+	usage  UsageUseCase
+	config config.Config
+
+	mu   sync.Mutex
+	jobs map[string]*models.TranslationJob
+}
+
+// NewTranslationUseCase crea una nueva instancia del use case de traducción
+func NewTranslationUseCase(chat services.ChatModel, budget BudgetUseCase, usage UsageUseCase, config config.Config) TranslationUseCase {
+	return &TranslationUseCaseImpl{
+		chat:   chat,
+		budget: budget,
+		usage:  usage,
+		config: config,
+		jobs:   make(map[string]*models.TranslationJob),
+	}
+}
+
+// Translate valida la solicitud y lanza un job que traduce la pista. Si ya hay un job
+// activo para el mismo video e idioma se retorna ese.
+func (s *TranslationUseCaseImpl) Translate(ctx context.Context, id string, req models.TranslationRequest) (*models.TranslationJob, error) {
+	if !utils.ValidateFilename(id) {
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+
+	target := strings.ToLower(strings.TrimSpace(req.TargetLanguage))
+	source := strings.ToLower(strings.TrimSpace(req.SourceLanguage))
+	if source == "" {
+		source = s.config.Subtitles.DefaultLanguage
+	}
+	for _, lang := range []string{target, source} {
+		if !languagePattern.MatchString(lang) {
+			return nil, NewValidationError("validation.language_code", lang)
+		}
+	}
+	if target == source {
+		return nil, NewValidationError("validation.translation_same_language")
+	}
+
+	cues, err := readTranscript(s.config, id, source)
+	if err != nil {
+		return nil, err
+	}
+	if len(cues) == 0 {
+		return nil, NewNotFoundError("not_found.transcript")
+	}
+
+	if !req.Overwrite {
+		if _, err := findTrack(s.config, id, target); err == nil {
+			return nil, NewValidationError("validation.track_exists", target)
+		}
+	}
+
+	if s.budget.Check(ctx) == BudgetHardLimit {
+		return nil, ErrBudgetExceeded
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for jobID, job := range s.jobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > translationJobTTL {
+			delete(s.jobs, jobID)
+			continue
+		}
+		if job.VideoID == id && job.TargetLanguage == target && job.FinishedAt == nil {
+			snapshot := *job
+			return &snapshot, nil
+		}
+	}
+
+	batchSize := s.config.Translation.BatchSize
+	job := &models.TranslationJob{
+		ID:             newJobID(),
+		VideoID:        id,
+		SourceLanguage: source,
+		TargetLanguage: target,
+		Status:         models.JobQueued,
+		Cues:           len(cues),
+		Batches:        (len(cues) + batchSize - 1) / batchSize,
+		CreatedAt:      now,
+	}
+	s.jobs[job.ID] = job
+
+	// El job sigue después de responder: conserva los valores del contexto (request id,
+	// API key) para el ledger, pero no su cancelación
+	go s.run(context.WithoutCancel(ctx), job.ID, cues)
+
+	log.Info(ctx, "Job de traducción creado",
+		log.String("job", job.ID),
+		log.String("video", id),
+		log.String("source", source),
+		log.String("target", target),
+		log.Int("cues", len(cues)),
+	)
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// GetJob retorna el estado de un job de traducción
+func (s *TranslationUseCaseImpl) GetJob(ctx context.Context, jobID string) (*models.TranslationJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return nil, NewNotFoundError("not_found.job")
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// GetJobs lista los jobs de traducción, del más reciente al más antiguo
+func (s *TranslationUseCaseImpl) GetJobs(ctx context.Context) (*models.TranslationJobsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := &models.TranslationJobsResponse{Jobs: make([]models.TranslationJob, 0, len(s.jobs))}
+	for _, job := range s.jobs {
+		response.Jobs = append(response.Jobs, *job)
+	}
+	sort.Slice(response.Jobs, func(i, j int) bool {
+		return response.Jobs[i].CreatedAt.After(response.Jobs[j].CreatedAt)
+	})
+	return response, nil
+}
+
+// update modifica el job bajo el lock
+func (s *TranslationUseCaseImpl) update(jobID string, fn func(job *models.TranslationJob)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.jobs[jobID])
+}
+
+// run traduce los cues por lotes, conservando los tiempos, y escribe la pista nueva
+func (s *TranslationUseCaseImpl) run(ctx context.Context, jobID string, cues []subtitles.Cue) {
+	var job models.TranslationJob
+	s.update(jobID, func(j *models.TranslationJob) {
+		j.Status = models.JobRunning
+		job = *j
+	})
+
+	if err := s.translateTrack(ctx, jobID, job, cues); err != nil {
+		log.Error(ctx, "Error en job de traducción", log.String("job", jobID), log.Err(err))
+		s.update(jobID, func(j *models.TranslationJob) {
+			now := time.Now().UTC()
+			j.Status = models.JobFailed
+			j.Error = err.Error()
+			j.FinishedAt = &now
+		})
+	}
+}
+
+func (s *TranslationUseCaseImpl) translateTrack(ctx context.Context, jobID string, job models.TranslationJob, cues []subtitles.Cue) error {
+	cfg := s.config.Translation
+	translated := make([]subtitles.Cue, len(cues))
+	copy(translated, cues)
+
+	for start := 0; start < len(cues); start += cfg.BatchSize {
+		if s.budget.Check(ctx) == BudgetHardLimit {
+			return ErrBudgetExceeded
+		}
+
+		end := min(start+cfg.BatchSize, len(cues))
+		var contextCues []translationCue
+		for i := max(0, start-cfg.ContextCues); i < start; i++ {
+			contextCues = append(contextCues, translationCue{Text: cues[i].Text, Translation: translated[i].Text})
+		}
+
+		texts, err := s.translateBatch(ctx, jobID, job, contextCues, cues[start:end])
+		if err != nil {
+			return err
+		}
+		for i, text := range texts {
+			translated[start+i].Text = subtitles.Wrap(text, cfg.MaxLineLength, cfg.MaxLines)
+		}
+
+		s.update(jobID, func(j *models.TranslationJob) { j.CompletedBatches++ })
+	}
+
+	path := filepath.Join(s.config.VideosPath, job.VideoID, "subtitles."+job.TargetLanguage+".vtt")
+	if err := writeVTTFile(path, translated); err != nil {
+		return fmt.Errorf("no se pudo guardar la pista: %v", err)
+	}
+
+	track, err := findTrack(s.config, job.VideoID, job.TargetLanguage)
+	if err != nil {
+		return err
+	}
+
+	s.update(jobID, func(j *models.TranslationJob) {
+		now := time.Now().UTC()
+		j.Status = models.JobCompleted
+		j.Track = &track
+		j.FinishedAt = &now
+		log.Info(ctx, "Job de traducción terminado",
+			log.String("job", jobID),
+			log.Int("cues", j.Cues),
+			log.Float("costo", j.CostUSD),
+		)
+	})
+	return nil
+}
+
+// translateBatch traduce un lote de cues. Si la respuesta del modelo es inválida o le
+// faltan cues (por ejemplo si se cortó por largo) el lote se divide en dos.
+func (s *TranslationUseCaseImpl) translateBatch(ctx context.Context, jobID string, job models.TranslationJob, contextCues []translationCue, cues []subtitles.Cue) ([]string, error) {
+	input := struct {
+		Context []translationCue `json:"context,omitempty"`
+		Cues    []translationCue `json:"cues"`
+	}{Context: contextCues, Cues: make([]translationCue, len(cues))}
+	for i, cue := range cues {
+		input.Cues[i] = translationCue{ID: i + 1, Text: strings.Join(strings.Fields(cue.Text), " ")}
+	}
+	query, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := s.config.Translation
+	answer, usage, err := s.chat.Generate(ctx, services.ChatRequest{
+		SystemPrompt: fmt.Sprintf(translationPrompt, languageName(job.SourceLanguage), languageName(job.TargetLanguage), cfg.MaxLineLength*cfg.MaxLines),
+		Query:        string(query),
+	})
+	// Una llamada fallida puede haberse cobrado igual
+	if err == nil || spent(usage) {
+		s.usage.Record(ctx, OperationTranslation, usage)
+		s.budget.Record(ctx, usage.CostUSD)
+		s.update(jobID, func(j *models.TranslationJob) {
+			j.CostUSD += usage.CostUSD
+			j.Model = usage.Model
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	texts, err := parseTranslations(answer, len(cues))
+	if err == nil {
+		return texts, nil
+	}
+	if len(cues) == 1 {
+		return nil, err
+	}
+
+	log.Warn(ctx, "Traducción incompleta, se divide el lote", log.String("job", jobID), log.Int("cues", len(cues)), log.Err(err))
+	half := len(cues) / 2
+	first, err := s.translateBatch(ctx, jobID, job, contextCues, cues[:half])
+	if err != nil {
+		return nil, err
+	}
+	secondContext := contextCues
+	for i, cue := range cues[:half] {
+		secondContext = append(secondContext, translationCue{Text: cue.Text, Translation: first[i]})
+	}
+	if len(secondContext) > cfg.ContextCues {
+		secondContext = secondContext[len(secondContext)-cfg.ContextCues:]
+	}
+	second, err := s.translateBatch(ctx, jobID, job, secondContext, cues[half:])
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// parseTranslations extrae las n traducciones de la respuesta del modelo, en orden de id
+func parseTranslations(answer string, n int) ([]string, error) {
+	var parsed struct {
+		Translations []translationCue `json:"translations"`
+	}
+	if err := parseModelJSON(answer, &parsed); err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, n)
+	for _, t := range parsed.Translations {
+		if t.ID >= 1 && t.ID <= n {
+			texts[t.ID-1] = strings.TrimSpace(t.Text)
+		}
+	}
+	for i, text := range texts {
+		if text == "" {
+			return nil, fmt.Errorf("falta la traducción del cue %d de %d", i+1, n)
+		}
+	}
+	return texts, nil
+}
+
+// writeVTTFile guarda los cues en WebVTT de forma atómica
+func writeVTTFile(path string, cues []subtitles.Cue) error {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := subtitles.WriteVTT(file, cues); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// languageName retorna el nombre del idioma para el prompt
+func languageName(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return fmt.Sprintf("el idioma %q", lang)
+}
+
+// newJobID genera un identificador aleatorio para un job
+func newJobID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
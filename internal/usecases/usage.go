@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/reqctx"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+	"github.com/ngrendenebos/scripts/transcribe-api/pkg/utils"
+)
+
+// Operaciones registradas en el ledger
+const (
+	OperationEmbedding   = "embedding"
+	OperationChat        = "chat"
+	OperationExpansion   = "expansion"
+	OperationHyDE        = "hyde"
+	OperationSummary     = "summary"
+	OperationChapters    = "chapters"
+	OperationTranslation = "translation"
+)
+
+// UsageGroups son las dimensiones de agregación soportadas por el reporte de uso
+var UsageGroups = []string{"day", "model", "route", "api_key"}
+
+// anonymousAPIKey agrupa el uso de clientes sin API key
+const anonymousAPIKey = "anonymous"
+
+// UsageUseCaseImpl registra y agrega el consumo de tokens y costos
+type UsageUseCaseImpl struct {
+	ledger *services.UsageLedger
+}
+
+// NewUsageUseCase crea una nueva instancia del use case de uso
+func NewUsageUseCase(ledger *services.UsageLedger) UsageUseCase {
+	return &UsageUseCaseImpl{
+		ledger: ledger,
+	}
+}
+
+// spent indica si una llamada consumió tokens o costo. Las llamadas de chat fallidas
+// pueden haberse cobrado igual y se registran solo en ese caso.
+func spent(usage models.TokenUsage) bool {
+	return usage.TotalTokens > 0 || usage.CostUSD > 0
+}
+
+// Record agrega al ledger el consumo de una llamada, con los datos del request en curso.
+// Un error de escritura se loggea pero no interrumpe la solicitud.
+func (u *UsageUseCaseImpl) Record(ctx context.Context, operation string, usage models.TokenUsage) {
+	entry := models.UsageEntry{
+		Timestamp:  time.Now().UTC(),
+		RequestID:  reqctx.RequestID(ctx),
+		ClientID:   reqctx.ClientID(ctx),
+		APIKey:     reqctx.APIKeyName(ctx),
+		Route:      reqctx.Route(ctx),
+		Operation:  operation,
+		TokenUsage: usage,
+	}
+
+	if err := u.ledger.Append(entry); err != nil {
+		log.Error(ctx, "Error registrando uso en ledger", log.Err(err), log.String("operation", operation))
+	}
+}
+
+// GetReport agrega los registros en [from, to) según las dimensiones indicadas
+func (u *UsageUseCaseImpl) GetReport(ctx context.Context, from, to time.Time, groupBy []string) (*models.UsageReportResponse, error) {
+	for _, group := range groupBy {
+		if !utils.ContainsString(UsageGroups, group) {
+			return nil, NewValidationError("validation.group_by", group, strings.Join(UsageGroups, ", "))
+		}
+	}
+
+	entries, err := u.ledger.Entries(from, to)
+	if err != nil {
+		return nil, NewInternalError(err, "error obteniendo uso")
+	}
+
+	rows := make(map[string]*models.UsageRow)
+	requests := make(map[string]map[string]bool)
+	total := models.UsageRow{}
+	totalRequests := make(map[string]bool)
+
+	for _, entry := range entries {
+		key, row := groupRow(entry, groupBy)
+		if existing, ok := rows[key]; ok {
+			row = existing
+		} else {
+			rows[key] = row
+			requests[key] = make(map[string]bool)
+		}
+
+		addUsage(row, entry)
+		addUsage(&total, entry)
+
+		// Las llamadas sin request id (trabajos en segundo plano, procesos fuera de HTTP)
+		// suman llamadas y costo pero no cuentan como solicitud
+		if entry.RequestID != "" {
+			requests[key][entry.RequestID] = true
+			totalRequests[entry.RequestID] = true
+		}
+	}
+
+	response := &models.UsageReportResponse{
+		From:    from.Format("2006-01-02"),
+		To:      to.AddDate(0, 0, -1).Format("2006-01-02"),
+		GroupBy: groupBy,
+		Rows:    make([]models.UsageRow, 0, len(rows)),
+	}
+
+	keys := make([]string, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		row := rows[key]
+		row.Requests = len(requests[key])
+		response.Rows = append(response.Rows, *row)
+	}
+
+	total.Requests = len(totalRequests)
+	response.Total = total
+
+	return response, nil
+}
+
+// groupRow arma la clave de agrupación y una fila vacía con las dimensiones del registro
+func groupRow(entry models.UsageEntry, groupBy []string) (string, *models.UsageRow) {
+	row := &models.UsageRow{}
+	parts := make([]string, 0, len(groupBy))
+
+	for _, group := range groupBy {
+		switch group {
+		case "day":
+			row.Day = entry.Timestamp.UTC().Format("2006-01-02")
+			parts = append(parts, row.Day)
+		case "model":
+			row.Model = entry.Model
+			parts = append(parts, row.Model)
+		case "route":
+			row.Route = entry.Route
+			parts = append(parts, row.Route)
+		case "api_key":
+			row.APIKey = entry.APIKey
+			if row.APIKey == "" {
+				row.APIKey = anonymousAPIKey
+			}
+			parts = append(parts, row.APIKey)
+		}
+	}
+
+	return strings.Join(parts, "\x00"), row
+}
+
+func addUsage(row *models.UsageRow, entry models.UsageEntry) {
+	row.Calls++
+	row.PromptTokens += entry.PromptTokens
+	row.CompletionTokens += entry.CompletionTokens
+	row.TotalTokens += entry.TotalTokens
+	row.CostUSD += entry.CostUSD
+}
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+)
+
+func TestUsageReportRequests(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	entry := func(requestID, model string, cost float64) models.UsageEntry {
+		return models.UsageEntry{
+			Timestamp:  day.Add(time.Hour),
+			RequestID:  requestID,
+			TokenUsage: models.TokenUsage{Model: model, TotalTokens: 10, CostUSD: cost},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		entries      []models.UsageEntry
+		wantRequests int
+		wantCalls    int
+	}{
+		{
+			name:         "una solicitud con varias llamadas",
+			entries:      []models.UsageEntry{entry("r1", "m", 1), entry("r1", "m", 1)},
+			wantRequests: 1,
+			wantCalls:    2,
+		},
+		{
+			name:         "solicitudes distintas",
+			entries:      []models.UsageEntry{entry("r1", "m", 1), entry("r2", "m", 1)},
+			wantRequests: 2,
+			wantCalls:    2,
+		},
+		{
+			name:         "llamadas sin request id no cuentan como solicitud",
+			entries:      []models.UsageEntry{entry("", "m", 1), entry("", "m", 1), entry("r1", "m", 1)},
+			wantRequests: 1,
+			wantCalls:    3,
+		},
+		{
+			name:         "solo trabajos en segundo plano",
+			entries:      []models.UsageEntry{entry("", "m", 1)},
+			wantRequests: 0,
+			wantCalls:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger, err := services.NewUsageLedger(filepath.Join(t.TempDir(), "usage.jsonl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ledger.Close()
+			for _, e := range tt.entries {
+				if err := ledger.Append(e); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			report, err := NewUsageUseCase(ledger).GetReport(context.Background(), day, day.AddDate(0, 0, 1), []string{"day"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Rows) != 1 {
+				t.Fatalf("Rows = %+v, want una fila", report.Rows)
+			}
+			for name, row := range map[string]models.UsageRow{"fila": report.Rows[0], "total": report.Total} {
+				if row.Requests != tt.wantRequests || row.Calls != tt.wantCalls {
+					t.Errorf("%s: requests %d calls %d, want requests %d calls %d", name, row.Requests, row.Calls, tt.wantRequests, tt.wantCalls)
+				}
+			}
+		})
+	}
+}
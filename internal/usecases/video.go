@@ -2,12 +2,20 @@ package usecases
 
 import (
 	"context"
-	"fmt"
-	"io"
+	"encoding/json"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/config"
+	"github.com/ngrendenebos/scripts/transcribe-api/cmd/api/log"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/i18n"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/models"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/services"
+	"github.com/ngrendenebos/scripts/transcribe-api/internal/subtitles"
 	"github.com/ngrendenebos/scripts/transcribe-api/pkg/utils"
 )
 
@@ -21,80 +29,183 @@ func NewVideoUseCase(config config.Config) VideoUseCase {
 	}
 }
 
-func (v *VideoUseCaseImpl) GetVideos(ctx context.Context) ([]byte, error) {
-	jsonFile, err := os.Open("videos.json")
+// GetVideos retorna los videos de videos.json con los idiomas de subtítulos disponibles
+// y las fuentes de reproducción de cada uno
+func (v *VideoUseCaseImpl) GetVideos(ctx context.Context) (*models.VideosData, error) {
+	jsonData, err := os.ReadFile("videos.json")
 	if err != nil {
-		return nil, fmt.Errorf("no se pudo leer el archivo de videos: %v", err)
+		return nil, NewInternalError(err, "no se pudo leer el archivo de videos")
 	}
-	defer jsonFile.Close()
 
-	jsonData, err := io.ReadAll(jsonFile)
-	if err != nil {
-		return nil, fmt.Errorf("no se pudo procesar el archivo de videos: %v", err)
+	var videos models.VideosData
+	if err := json.Unmarshal(jsonData, &videos); err != nil {
+		return nil, NewInternalError(err, "no se pudo procesar el archivo de videos")
+	}
+
+	for i, video := range videos.Videos {
+		if !utils.ValidateFilename(video.ID) {
+			continue
+		}
+		if video.Prefer != "" && video.Prefer != models.VideoSourceHLS && video.Prefer != models.VideoSourceMP4 {
+			log.Warn(ctx, "prefer inválido en videos.json, se usa hls", log.String("video", video.ID), log.String("prefer", video.Prefer))
+			video.Prefer = ""
+			videos.Videos[i].Prefer = ""
+		}
+		videos.Videos[i].Sources = videoSources(v.config, video)
+
+		tracks, err := listTracks(v.config, video.ID)
+		if err != nil {
+			log.Warn(ctx, "Error listando subtítulos del video", log.String("video", video.ID), log.Err(err))
+			continue
+		}
+		for _, track := range tracks {
+			videos.Videos[i].SubtitleLanguages = append(videos.Videos[i].SubtitleLanguages, track.Language)
+		}
 	}
 
-	return jsonData, nil
+	return &videos, nil
 }
 
 func (v *VideoUseCaseImpl) GetVideo(ctx context.Context, filename string) (string, error) {
 	if !utils.ValidateFilename(filename) {
-		return "", fmt.Errorf("nombre de archivo inválido")
+		return "", NewValidationError("validation.invalid_filename")
 	}
 
 	videoPath := filepath.Join(v.config.VideosPath, filename, "video.mp4")
 
 	if _, err := os.Stat(videoPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("archivo de video no encontrado")
+		return "", NewNotFoundError("not_found.video")
 	}
 
 	return videoPath, nil
 }
 
-func (v *VideoUseCaseImpl) GetSubtitles(ctx context.Context, id string) (string, error) {
+// GetSubtitles elige la pista de subtítulos: el idioma pedido si se indicó, si no el
+// primero de Accept-Language que tenga pista, y si no el idioma original del video
+func (v *VideoUseCaseImpl) GetSubtitles(ctx context.Context, id, language, acceptLanguage string) (*models.SubtitleTrack, error) {
 	if !utils.ValidateFilename(id) {
-		return "", fmt.Errorf("nombre de archivo inválido")
+		return nil, NewValidationError("validation.invalid_filename")
+	}
+
+	if language != "" {
+		track, err := findTrack(v.config, id, strings.ToLower(language))
+		if err != nil {
+			return nil, err
+		}
+		return &track, nil
 	}
 
-	subtitlePath := filepath.Join(v.config.VideosPath, id, "subtitles.vtt")
+	tracks, err := listTracks(v.config, id)
+	if err != nil {
+		return nil, NewInternalError(err, "no se pudieron listar los subtítulos")
+	}
+	if len(tracks) == 0 {
+		return nil, NewNotFoundError("not_found.subtitles")
+	}
 
-	if _, err := os.Stat(subtitlePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("archivo de subtítulos no encontrado")
+	for _, lang := range i18n.ParseAcceptLanguage(acceptLanguage) {
+		for _, track := range tracks {
+			if track.Language == lang {
+				return &track, nil
+			}
+		}
 	}
 
-	return subtitlePath, nil
+	// La pista del idioma original es la primera, si existe
+	return &tracks[0], nil
 }
 
-func (v *VideoUseCaseImpl) GetThumbnail(ctx context.Context, id string) (string, error) {
+// GetSubtitleTracks lista las pistas de subtítulos del video
+func (v *VideoUseCaseImpl) GetSubtitleTracks(ctx context.Context, id string) (*models.SubtitleTracksResponse, error) {
 	if !utils.ValidateFilename(id) {
-		return "", fmt.Errorf("nombre de archivo inválido")
+		return nil, NewValidationError("validation.invalid_filename")
 	}
 
-	thumbnailPath := filepath.Join(v.config.VideosPath, id, "thumbnail.jpg")
+	if _, err := os.Stat(filepath.Join(v.config.VideosPath, id)); os.IsNotExist(err) {
+		return nil, NewNotFoundError("not_found.video")
+	}
 
-	if _, err := os.Stat(thumbnailPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("archivo de miniatura no encontrado")
+	tracks, err := listTracks(v.config, id)
+	if err != nil {
+		return nil, NewInternalError(err, "no se pudieron listar los subtítulos")
 	}
 
-	return thumbnailPath, nil
+	return &models.SubtitleTracksResponse{VideoID: id, Tracks: tracks}, nil
 }
 
-func (v *VideoUseCaseImpl) GetSummary(ctx context.Context, id string) (string, error) {
-	if !utils.ValidateFilename(id) {
-		return "", fmt.Errorf("nombre de archivo inválido")
+// GetTranscript arma la transcripción del video en párrafos, opcionalmente limitada a
+// la ventana [from, to) y con los términos de Highlight marcados
+func (v *VideoUseCaseImpl) GetTranscript(ctx context.Context, id string, query models.TranscriptQuery) (*models.TranscriptResponse, error) {
+	from, err := parseTimeParam("from", query.From, 0)
+	if err != nil {
+		return nil, err
 	}
-
-	summaryPath := filepath.Join(v.config.VideosPath, id, "summary.txt")
-
-	file, err := os.Open(summaryPath)
-	defer file.Close()
+	to, err := parseTimeParam("to", query.To, 0)
 	if err != nil {
-		return "", fmt.Errorf("no se encontrado por archivo de summary.txt")
+		return nil, err
+	}
+	if query.To != "" && to <= from {
+		return nil, NewValidationError("validation.time_range")
 	}
 
-	content, err := io.ReadAll(file)
+	track, err := v.GetSubtitles(ctx, id, query.Language, query.AcceptLanguage)
 	if err != nil {
-		return "", fmt.Errorf("no se encontrado por archivo de summary.txt")
+		return nil, err
 	}
+	cues, err := subtitles.ReadFile(track.Path)
+	if err != nil {
+		return nil, NewInternalError(err, "error leyendo subtítulos")
+	}
+
+	var window []subtitles.Cue
+	for _, cue := range cues {
+		if cue.End > from && (query.To == "" || cue.Start < to) {
+			window = append(window, cue)
+		}
+	}
+	if query.To == "" && len(cues) > 0 {
+		to = cues[len(cues)-1].End
+	}
+
+	cfg := v.config.Transcript
+	paragraphs := subtitles.Paragraphs(window, subtitles.FromSeconds(cfg.ParagraphPauseSeconds), subtitles.FromSeconds(cfg.ParagraphSeconds))
+	terms := highlightTerms(query.Highlight)
+
+	response := &models.TranscriptResponse{
+		VideoID:    id,
+		Language:   track.Language,
+		FromSec:    from.Seconds(),
+		ToSec:      max(to, from).Seconds(),
+		Paragraphs: make([]models.TranscriptParagraph, len(paragraphs)),
+	}
+	for i, paragraph := range paragraphs {
+		response.Paragraphs[i] = models.TranscriptParagraph{
+			StartSec:   paragraph.Start.Seconds(),
+			EndSec:     paragraph.End.Seconds(),
+			Timestamp:  services.FormatTimestamp(paragraph.Start.Seconds()),
+			Text:       paragraph.Text,
+			Highlights: highlight(paragraph.Text, terms),
+		}
+	}
+
+	return response, nil
+}
 
-	return string(content), nil
+// parseTimeParam parsea un parámetro de tiempo en segundos ("90") o como timestamp
+// ("1:30", "00:01:30.000"). Si está vacío retorna fallback.
+func parseTimeParam(name, value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return 0, NewValidationError("validation.time_param", name)
+		}
+		return subtitles.FromSeconds(seconds), nil
+	}
+	d, err := subtitles.ParseTimestamp(value)
+	if err != nil || d < 0 {
+		return 0, NewValidationError("validation.time_param", name)
+	}
+	return d, nil
 }